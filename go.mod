@@ -13,6 +13,7 @@ require (
 	github.com/redis/go-redis/v9 v9.5.4
 	go.uber.org/automaxprocs v1.5.3
 	google.golang.org/grpc v1.65.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
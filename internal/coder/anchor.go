@@ -0,0 +1,71 @@
+package coder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+)
+
+// AnchorDiscriminator returns the 8 byte prefix Anchor puts in front of the
+// arguments of the named instruction.
+func AnchorDiscriminator(name string) [8]byte {
+	return anchorDiscriminator("global", name)
+}
+
+// AnchorAccountDiscriminator returns the 8 byte prefix of the named account type.
+func AnchorAccountDiscriminator(name string) [8]byte {
+	return anchorDiscriminator("account", name)
+}
+
+func anchorDiscriminator(namespace string, name string) [8]byte {
+	var discriminator [8]byte
+	hash := sha256.Sum256([]byte(namespace + ":" + name))
+	copy(discriminator[:], hash[:8])
+	return discriminator
+}
+
+// readDiscriminator reads the 8 byte Anchor discriminator at the start of data.
+func readDiscriminator(data []byte) (*bytes.Reader, [8]byte, error) {
+	var discriminator [8]byte
+	buf := bytes.NewReader(data)
+	err := read(buf, &discriminator)
+	return buf, discriminator, err
+}
+
+// decodeAnchorAccount checks the account discriminator and reads the leading fields
+// of the account into state. Accounts are longer than the fields we read.
+func decodeAnchorAccount(name string, expected [8]byte, data []byte, state interface{}) error {
+	buf, discriminator, err := readDiscriminator(data)
+	if err != nil {
+		return err
+	}
+
+	if discriminator != expected {
+		return fmt.Errorf("%w: account is not a %s", ErrUnknownDiscriminator, name)
+	}
+
+	if size := binary.Size(state); buf.Len() < size {
+		return fmt.Errorf("%w: %s needs %d bytes, got %d", ErrShortData, name, size, buf.Len())
+	}
+
+	return read(buf, state)
+}
+
+// readBorshString reads a u32 length followed by that many bytes.
+func readBorshString(buf *bytes.Reader) (string, error) {
+	var length uint32
+	if err := read(buf, &length); err != nil {
+		return "", err
+	}
+
+	if int64(length) > int64(buf.Len()) {
+		return "", fmt.Errorf("%w: string expects %d bytes, got %d", ErrShortData, length, buf.Len())
+	}
+
+	value := make([]byte, length)
+	if err := read(buf, value); err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
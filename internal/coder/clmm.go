@@ -0,0 +1,211 @@
+package coder
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	clmmIncreaseLiquidity   = AnchorDiscriminator("increase_liquidity")
+	clmmIncreaseLiquidityV2 = AnchorDiscriminator("increase_liquidity_v2")
+	clmmDecreaseLiquidity   = AnchorDiscriminator("decrease_liquidity")
+	clmmDecreaseLiquidityV2 = AnchorDiscriminator("decrease_liquidity_v2")
+	clmmClosePosition       = AnchorDiscriminator("close_position")
+	clmmPoolState           = AnchorAccountDiscriminator("PoolState")
+	clmmPersonalPosition    = AnchorAccountDiscriminator("PersonalPositionState")
+)
+
+// Account positions in the CLMM liquidity instructions, which are the same in the
+// v1 and v2 variants. Increase and decrease order the pool and position differently.
+const (
+	CLMM_LIQUIDITY_OWNER   = 0
+	CLMM_INCREASE_POOL     = 2
+	CLMM_INCREASE_POSITION = 4
+	CLMM_INCREASE_VAULT_0  = 9
+	CLMM_INCREASE_VAULT_1  = 10
+	CLMM_DECREASE_POSITION = 2
+	CLMM_DECREASE_POOL     = 3
+	CLMM_DECREASE_VAULT_0  = 5
+	CLMM_DECREASE_VAULT_1  = 6
+	CLMM_CLOSE_OWNER       = 0
+	CLMM_CLOSE_POSITION    = 3
+)
+
+// Uint128 is a little endian u128 as laid out by the CLMM program.
+type Uint128 struct {
+	Lo uint64
+	Hi uint64
+}
+
+func (u Uint128) BigInt() *big.Int {
+	value := new(big.Int).SetUint64(u.Hi)
+	value.Lsh(value, 64)
+	return value.Or(value, new(big.Int).SetUint64(u.Lo))
+}
+
+func (u Uint128) IsZero() bool {
+	return u.Lo == 0 && u.Hi == 0
+}
+
+// ClmmIncreaseLiquidity is decoded from both increase_liquidity and
+// increase_liquidity_v2. BaseFlag is only set by v2 callers that pass it.
+type ClmmIncreaseLiquidity struct {
+	Liquidity  Uint128
+	Amount0Max uint64
+	Amount1Max uint64
+	BaseFlag   *bool
+	V2         bool
+}
+
+// ClmmDecreaseLiquidity is decoded from both decrease_liquidity and
+// decrease_liquidity_v2, which take the same arguments.
+type ClmmDecreaseLiquidity struct {
+	Liquidity  Uint128
+	Amount0Min uint64
+	Amount1Min uint64
+	V2         bool
+}
+
+type ClmmClosePosition struct{}
+
+// ClmmPoolState is the leading part of a CLMM pool account, up to the current tick.
+type ClmmPoolState struct {
+	Bump           uint8
+	AmmConfig      solana.PublicKey
+	Owner          solana.PublicKey
+	TokenMint0     solana.PublicKey
+	TokenMint1     solana.PublicKey
+	TokenVault0    solana.PublicKey
+	TokenVault1    solana.PublicKey
+	ObservationKey solana.PublicKey
+	MintDecimals0  uint8
+	MintDecimals1  uint8
+	TickSpacing    uint16
+	Liquidity      Uint128
+	SqrtPriceX64   Uint128
+	TickCurrent    int32
+}
+
+// Price returns the price of token 0 in token 1, adjusted for decimals.
+func (state ClmmPoolState) Price() *big.Float {
+	return sqrtPriceX64ToPrice(state.SqrtPriceX64, int(state.MintDecimals0), int(state.MintDecimals1))
+}
+
+// ClmmPersonalPosition is the leading part of a CLMM position account.
+type ClmmPersonalPosition struct {
+	Bump           uint8
+	NftMint        solana.PublicKey
+	PoolId         solana.PublicKey
+	TickLowerIndex int32
+	TickUpperIndex int32
+	Liquidity      Uint128
+}
+
+// RaydiumClmmCoder decodes Raydium CLMM liquidity instructions and pool and position state.
+type RaydiumClmmCoder struct{}
+
+func NewRaydiumClmmCoder() *RaydiumClmmCoder {
+	return &RaydiumClmmCoder{}
+}
+
+// Decode decodes the given byte array into an instruction.
+func (coder *RaydiumClmmCoder) Decode(data []byte) (interface{}, error) {
+	decoded, err := decodeClmm(data)
+	if err != nil {
+		recordDecodeFailure(PROGRAM_RAYDIUM_CLMM, err)
+	}
+	return decoded, err
+}
+
+func (coder *RaydiumClmmCoder) DecodePoolState(data []byte) (ClmmPoolState, error) {
+	var state ClmmPoolState
+	err := decodeAnchorAccount("ClmmPoolState", clmmPoolState, data, &state)
+	return state, err
+}
+
+func (coder *RaydiumClmmCoder) DecodePersonalPosition(data []byte) (ClmmPersonalPosition, error) {
+	var position ClmmPersonalPosition
+	err := decodeAnchorAccount("ClmmPersonalPosition", clmmPersonalPosition, data, &position)
+	return position, err
+}
+
+func decodeClmm(data []byte) (interface{}, error) {
+	buf, discriminator, err := readDiscriminator(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch discriminator {
+	case clmmIncreaseLiquidity, clmmIncreaseLiquidityV2:
+		return decodeClmmIncreaseLiquidity(buf, discriminator == clmmIncreaseLiquidityV2)
+	case clmmDecreaseLiquidity, clmmDecreaseLiquidityV2:
+		instruction := ClmmDecreaseLiquidity{V2: discriminator == clmmDecreaseLiquidityV2}
+		if err := expectLength("ClmmDecreaseLiquidity", buf, 32); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction.Liquidity, &instruction.Amount0Min, &instruction.Amount1Min)
+	case clmmClosePosition:
+		return ClmmClosePosition{}, expectLength("ClmmClosePosition", buf, 0)
+	default:
+		return nil, unknownDiscriminator(discriminator)
+	}
+}
+
+func decodeClmmIncreaseLiquidity(buf *bytes.Reader, v2 bool) (ClmmIncreaseLiquidity, error) {
+	instruction := ClmmIncreaseLiquidity{V2: v2}
+
+	sizes := []int{32}
+	if v2 {
+		// base_flag is an Option<bool>: a zero tag, or a one tag and the value
+		sizes = []int{33, 34}
+	}
+
+	if err := expectLength("ClmmIncreaseLiquidity", buf, sizes...); err != nil {
+		return instruction, err
+	}
+
+	if err := read(buf, &instruction.Liquidity, &instruction.Amount0Max, &instruction.Amount1Max); err != nil {
+		return instruction, err
+	}
+
+	if v2 {
+		var tag uint8
+		if err := read(buf, &tag); err != nil {
+			return instruction, err
+		}
+
+		if tag == 1 {
+			instruction.BaseFlag = new(bool)
+			if err := read(buf, instruction.BaseFlag); err != nil {
+				return instruction, err
+			}
+		}
+
+		if err := expectLength("ClmmIncreaseLiquidity", buf, 0); err != nil {
+			return instruction, err
+		}
+	}
+
+	return instruction, nil
+}
+
+// sqrtPriceX64ToPrice converts a Q64.64 square root price into the price of the
+// first token in the second, adjusted for decimals.
+func sqrtPriceX64ToPrice(sqrtPriceX64 Uint128, decimals0 int, decimals1 int) *big.Float {
+	sqrtPrice := new(big.Float).SetInt(sqrtPriceX64.BigInt())
+	sqrtPrice.Quo(sqrtPrice, new(big.Float).SetMantExp(big.NewFloat(1), 64))
+
+	price := new(big.Float).Mul(sqrtPrice, sqrtPrice)
+	decimals := decimals0 - decimals1
+
+	if decimals >= 0 {
+		return price.Mul(price, pow10(decimals))
+	}
+	return price.Quo(price, pow10(-decimals))
+}
+
+func pow10(exponent int) *big.Float {
+	return new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil))
+}
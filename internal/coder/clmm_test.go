@@ -0,0 +1,146 @@
+package coder
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestRaydiumClmmDecode(t *testing.T) {
+	liquidity := Uint128{Lo: 5, Hi: 1}
+
+	runDecodeTests(t, NewRaydiumClmmCoder(), []decodeTest{
+		{
+			name: "increase_liquidity",
+			data: append(disc("2e9cf3760dcdfbb2"), le(liquidity, uint64(100), uint64(200))...),
+			want: ClmmIncreaseLiquidity{Liquidity: liquidity, Amount0Max: 100, Amount1Max: 200},
+		},
+		{
+			name: "increase_liquidity_v2 without base flag",
+			data: append(disc("851d59df45eeb00a"), le(liquidity, uint64(100), uint64(200), uint8(0))...),
+			want: ClmmIncreaseLiquidity{Liquidity: liquidity, Amount0Max: 100, Amount1Max: 200, V2: true},
+		},
+		{
+			name: "increase_liquidity_v2 with base flag",
+			data: append(disc("851d59df45eeb00a"), le(liquidity, uint64(100), uint64(200), uint8(1), true)...),
+			want: ClmmIncreaseLiquidity{Liquidity: liquidity, Amount0Max: 100, Amount1Max: 200, BaseFlag: ptr(true), V2: true},
+		},
+		{
+			name: "decrease_liquidity",
+			data: append(disc("a026d06f685b2c01"), le(liquidity, uint64(1), uint64(2))...),
+			want: ClmmDecreaseLiquidity{Liquidity: liquidity, Amount0Min: 1, Amount1Min: 2},
+		},
+		{
+			name: "decrease_liquidity_v2",
+			data: append(disc("3a7fbc3e4f52c460"), le(liquidity, uint64(1), uint64(2))...),
+			want: ClmmDecreaseLiquidity{Liquidity: liquidity, Amount0Min: 1, Amount1Min: 2, V2: true},
+		},
+		{
+			name: "close_position",
+			data: disc("7b86510031446262"),
+			want: ClmmClosePosition{},
+		},
+		{name: "short increase", data: append(disc("2e9cf3760dcdfbb2"), le(liquidity)...), wantErr: ErrShortData},
+		{name: "v1 increase with base flag", data: append(disc("2e9cf3760dcdfbb2"), le(liquidity, uint64(1), uint64(2), uint8(0))...), wantErr: ErrTrailingData},
+		{name: "v2 increase tag without flag", data: append(disc("851d59df45eeb00a"), le(liquidity, uint64(1), uint64(2), uint8(1))...), wantErr: ErrShortData},
+		{name: "short decrease", data: append(disc("a026d06f685b2c01"), le(uint64(1))...), wantErr: ErrShortData},
+		{name: "close with data", data: append(disc("7b86510031446262"), 0), wantErr: ErrTrailingData},
+		{name: "unknown discriminator", data: disc("f8c69e91e17587c8"), wantErr: ErrUnknownDiscriminator},
+	})
+}
+
+func TestRaydiumClmmDecodePoolState(t *testing.T) {
+	var (
+		mint0  = testKey(10)
+		mint1  = testKey(20)
+		vault0 = testKey(30)
+		vault1 = testKey(40)
+	)
+
+	// Offsets of the on-chain PoolState, which is 1544 bytes
+	data := account(1544, map[int]interface{}{
+		0:   disc("f7ede3f5d7c3de46"),
+		73:  mint0,
+		105: mint1,
+		137: vault0,
+		169: vault1,
+		233: uint8(9),
+		234: uint8(6),
+		235: uint16(60),
+		237: Uint128{Lo: 1_000_000},
+		// A square root price of 2^64 is a raw price of 1
+		253: Uint128{Hi: 1},
+		269: int32(-120),
+	})
+
+	state, err := NewRaydiumClmmCoder().DecodePoolState(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.TokenMint0 != mint0 || state.TokenMint1 != mint1 || state.TokenVault0 != vault0 || state.TokenVault1 != vault1 {
+		t.Errorf("keys = %s %s %s %s", state.TokenMint0, state.TokenMint1, state.TokenVault0, state.TokenVault1)
+	}
+	if state.MintDecimals0 != 9 || state.MintDecimals1 != 6 || state.TickSpacing != 60 {
+		t.Errorf("decimals = %d %d, tick spacing = %d", state.MintDecimals0, state.MintDecimals1, state.TickSpacing)
+	}
+	if state.Liquidity != (Uint128{Lo: 1_000_000}) || state.TickCurrent != -120 {
+		t.Errorf("liquidity = %v, tick = %d", state.Liquidity.BigInt(), state.TickCurrent)
+	}
+
+	// One raw unit of token 1 per raw unit of token 0 is 1000 whole tokens 1 per
+	// whole token 0 with 9 and 6 decimals
+	if price, _ := state.Price().Float64(); price != 1000 {
+		t.Errorf("Price() = %v, want 1000", price)
+	}
+
+	if _, err := NewRaydiumClmmCoder().DecodePoolState(data[:200]); !errors.Is(err, ErrShortData) {
+		t.Errorf("truncated account: err = %v, want %v", err, ErrShortData)
+	}
+}
+
+func TestRaydiumClmmDecodePersonalPosition(t *testing.T) {
+	pool := testKey(50)
+
+	data := account(281, map[int]interface{}{
+		0:  disc("466f967ee60f1975"),
+		9:  testKey(60),
+		41: pool,
+		73: int32(-600),
+		77: int32(600),
+		81: Uint128{Lo: 42},
+	})
+
+	position, err := NewRaydiumClmmCoder().DecodePersonalPosition(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if position.PoolId != pool || position.TickLowerIndex != -600 || position.TickUpperIndex != 600 || position.Liquidity != (Uint128{Lo: 42}) {
+		t.Errorf("position = %+v", position)
+	}
+
+	// A pool account is not a position
+	if _, err := NewRaydiumClmmCoder().DecodePersonalPosition(account(1544, map[int]interface{}{0: disc("f7ede3f5d7c3de46")})); !errors.Is(err, ErrUnknownDiscriminator) {
+		t.Errorf("pool account: err = %v, want %v", err, ErrUnknownDiscriminator)
+	}
+}
+
+func TestUint128BigInt(t *testing.T) {
+	tests := []struct {
+		value Uint128
+		want  string
+	}{
+		{Uint128{}, "0"},
+		{Uint128{Lo: 42}, "42"},
+		{Uint128{Hi: 1}, "18446744073709551616"},
+		{Uint128{Lo: ^uint64(0), Hi: ^uint64(0)}, "340282366920938463463374607431768211455"},
+	}
+
+	for _, tt := range tests {
+		want, _ := new(big.Int).SetString(tt.want, 10)
+		if got := tt.value.BigInt(); got.Cmp(want) != 0 {
+			t.Errorf("%+v.BigInt() = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
@@ -1,5 +1,12 @@
 package coder
 
+import "github.com/gagliardetto/solana-go"
+
+type Initialize struct {
+	Nonce    byte
+	OpenTime uint64
+}
+
 type Initialize2 struct {
 	Nonce          byte
 	OpenTime       uint64
@@ -7,7 +14,56 @@ type Initialize2 struct {
 	InitCoinAmount uint64
 }
 
+type MonitorStep struct {
+	PlanOrderLimit   uint16
+	PlaceOrderLimit  uint16
+	CancelOrderLimit uint16
+}
+
+// Deposit adds liquidity. BaseSide 0 fixes the coin amount, 1 fixes the pc amount.
+type Deposit struct {
+	MaxCoinAmount  uint64
+	MaxPcAmount    uint64
+	BaseSide       uint64
+	OtherAmountMin *uint64
+}
+
 type Withdraw struct {
+	Amount        uint64
+	MinCoinAmount *uint64
+	MinPcAmount   *uint64
+}
+
+type MigrateToOpenBook struct{}
+
+// SetParams is an admin instruction. Which optional field is set depends on Param.
+type SetParams struct {
+	Param             uint8
+	Value             *uint64
+	NewPubkey         *solana.PublicKey
+	Fees              *AmmFees
+	LastOrderDistance *LastOrderDistance
+}
+
+type AmmFees struct {
+	MinSeparateNumerator   uint64
+	MinSeparateDenominator uint64
+	TradeFeeNumerator      uint64
+	TradeFeeDenominator    uint64
+	PnlNumerator           uint64
+	PnlDenominator         uint64
+	SwapFeeNumerator       uint64
+	SwapFeeDenominator     uint64
+}
+
+type LastOrderDistance struct {
+	LastOrderNumerator   uint64
+	LastOrderDenominator uint64
+}
+
+type WithdrawPnl struct{}
+
+type WithdrawSrm struct {
 	Amount uint64
 }
 
@@ -16,17 +72,32 @@ type SwapBaseIn struct {
 	MinimumAmountOut uint64
 }
 
+type PreInitialize struct {
+	Nonce byte
+}
+
 type SwapBaseOut struct {
 	MaxAmountIn uint64
 	AmountOut   uint64
 }
 
-type Compute struct {
-	Instruction uint8
-	Value       uint32
+// SimulateInfo carries SwapBaseIn when Param is 1 and SwapBaseOut when Param is 2.
+type SimulateInfo struct {
+	Param       uint8
+	SwapBaseIn  *SwapBaseIn
+	SwapBaseOut *SwapBaseOut
 }
 
-type Transfer struct {
-	Instruction uint32
-	Amount      int64
+type AdminCancelOrders struct {
+	Limit uint16
+}
+
+type CreateConfigAccount struct{}
+
+// UpdateConfigAccount sets a new owner when Param is 0 or 1, and the pool
+// creation fee when Param is 2.
+type UpdateConfigAccount struct {
+	Param         uint8
+	Owner         *solana.PublicKey
+	CreatePoolFee *uint64
 }
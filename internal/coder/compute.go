@@ -0,0 +1,143 @@
+package coder
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"slices"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const (
+	// Runtime defaults when a transaction does not set its own compute unit limit
+	DEFAULT_INSTRUCTION_COMPUTE_UNIT_LIMIT = 200_000
+	DEFAULT_BUILTIN_COMPUTE_UNIT_LIMIT     = 3_000
+	MAX_COMPUTE_UNIT_LIMIT                 = 1_400_000
+	MICRO_LAMPORTS_PER_LAMPORT             = 1_000_000
+)
+
+// builtinPrograms are the native programs the runtime only reserves
+// DEFAULT_BUILTIN_COMPUTE_UNIT_LIMIT for in the default limit
+var builtinPrograms = []solana.PublicKey{
+	solana.SystemProgramID,
+	solana.VoteProgramID,
+	solana.StakeProgramID,
+	solana.ConfigProgramID,
+	solana.BPFLoaderDeprecatedProgramID,
+	solana.BPFLoaderProgramID,
+	solana.BPFLoaderUpgradeableProgramID,
+	solana.MustPublicKeyFromBase58("LoaderV411111111111111111111111111111111111"),
+	solana.MustPublicKeyFromBase58("AddressLookupTab1e1111111111111111111111111"),
+	solana.ComputeBudget,
+}
+
+type RequestUnitsDeprecated struct {
+	Units         uint32
+	AdditionalFee uint32
+}
+
+type RequestHeapFrame struct {
+	Bytes uint32
+}
+
+type SetComputeUnitLimit struct {
+	Units uint32
+}
+
+// SetComputeUnitPrice is the priority fee in micro-lamports per compute unit.
+type SetComputeUnitPrice struct {
+	MicroLamports uint64
+}
+
+type SetLoadedAccountsDataSizeLimit struct {
+	Bytes uint32
+}
+
+// ComputeBudgetCoder decodes ComputeBudget program instructions.
+type ComputeBudgetCoder struct{}
+
+func NewComputeBudgetCoder() *ComputeBudgetCoder {
+	return &ComputeBudgetCoder{}
+}
+
+// Decode decodes the given byte array into an instruction.
+func (coder *ComputeBudgetCoder) Decode(data []byte) (interface{}, error) {
+	decoded, err := decodeComputeBudget(data)
+	if err != nil {
+		recordDecodeFailure(PROGRAM_COMPUTE_BUDGET, err)
+	}
+	return decoded, err
+}
+
+func decodeComputeBudget(data []byte) (interface{}, error) {
+	buf := bytes.NewReader(data)
+	var instructionID byte
+	if err := read(buf, &instructionID); err != nil {
+		return nil, err
+	}
+
+	switch instructionID {
+	case 0:
+		var instruction RequestUnitsDeprecated
+		if err := expectLength("RequestUnitsDeprecated", buf, 8); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction.Units, &instruction.AdditionalFee)
+	case 1:
+		var instruction RequestHeapFrame
+		if err := expectLength("RequestHeapFrame", buf, 4); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction.Bytes)
+	case 2:
+		var instruction SetComputeUnitLimit
+		if err := expectLength("SetComputeUnitLimit", buf, 4); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction.Units)
+	case 3:
+		var instruction SetComputeUnitPrice
+		if err := expectLength("SetComputeUnitPrice", buf, 8); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction.MicroLamports)
+	case 4:
+		var instruction SetLoadedAccountsDataSizeLimit
+		if err := expectLength("SetLoadedAccountsDataSizeLimit", buf, 4); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction.Bytes)
+	default:
+		return nil, unknownDiscriminator(instructionID)
+	}
+}
+
+// PriorityFee returns the priority fee in lamports for the given compute units,
+// rounded up. The runtime charges the price on the requested limit whether or not
+// the units are consumed, so passing the consumed units instead gives the share of
+// the fee that paid for work actually done.
+func PriorityFee(computeUnits uint64, microLamports uint64) uint64 {
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(computeUnits), new(big.Int).SetUint64(microLamports))
+	fee.Add(fee, big.NewInt(MICRO_LAMPORTS_PER_LAMPORT-1))
+	fee.Div(fee, big.NewInt(MICRO_LAMPORTS_PER_LAMPORT))
+
+	if !fee.IsUint64() {
+		return math.MaxUint64
+	}
+	return fee.Uint64()
+}
+
+// DefaultComputeUnitLimit is the limit the runtime applies to a transaction with no
+// SetComputeUnitLimit, given its number of builtin, ComputeBudget included, and other
+// top-level instructions.
+func DefaultComputeUnitLimit(instructions int, builtins int) uint32 {
+	limit := instructions*DEFAULT_INSTRUCTION_COMPUTE_UNIT_LIMIT + builtins*DEFAULT_BUILTIN_COMPUTE_UNIT_LIMIT
+	return uint32(min(limit, MAX_COMPUTE_UNIT_LIMIT))
+}
+
+// IsBuiltinProgram reports whether the runtime counts an instruction of program as
+// a builtin when it works out the default compute unit limit.
+func IsBuiltinProgram(program solana.PublicKey) bool {
+	return slices.Contains(builtinPrograms, program)
+}
@@ -0,0 +1,143 @@
+package coder
+
+import (
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	cpmmInitialize     = AnchorDiscriminator("initialize")
+	cpmmDeposit        = AnchorDiscriminator("deposit")
+	cpmmWithdraw       = AnchorDiscriminator("withdraw")
+	cpmmSwapBaseInput  = AnchorDiscriminator("swap_base_input")
+	cpmmSwapBaseOutput = AnchorDiscriminator("swap_base_output")
+	cpmmPoolState      = AnchorAccountDiscriminator("PoolState")
+)
+
+// Account positions shared by the CPMM instructions
+const (
+	CPMM_INITIALIZE_POOL         = 3
+	CPMM_INITIALIZE_TOKEN_0_MINT = 4
+	CPMM_INITIALIZE_TOKEN_1_MINT = 5
+	CPMM_LIQUIDITY_OWNER         = 0
+	CPMM_LIQUIDITY_POOL          = 2
+	CPMM_SWAP_PAYER              = 0
+	CPMM_SWAP_POOL               = 3
+)
+
+type CpmmInitialize struct {
+	InitAmount0 uint64
+	InitAmount1 uint64
+	OpenTime    uint64
+}
+
+type CpmmDeposit struct {
+	LpTokenAmount       uint64
+	MaximumToken0Amount uint64
+	MaximumToken1Amount uint64
+}
+
+type CpmmWithdraw struct {
+	LpTokenAmount       uint64
+	MinimumToken0Amount uint64
+	MinimumToken1Amount uint64
+}
+
+type CpmmSwapBaseInput struct {
+	AmountIn         uint64
+	MinimumAmountOut uint64
+}
+
+type CpmmSwapBaseOutput struct {
+	MaxAmountIn uint64
+	AmountOut   uint64
+}
+
+type CpmmPoolState struct {
+	AmmConfig          solana.PublicKey
+	PoolCreator        solana.PublicKey
+	Token0Vault        solana.PublicKey
+	Token1Vault        solana.PublicKey
+	LpMint             solana.PublicKey
+	Token0Mint         solana.PublicKey
+	Token1Mint         solana.PublicKey
+	Token0Program      solana.PublicKey
+	Token1Program      solana.PublicKey
+	ObservationKey     solana.PublicKey
+	AuthBump           uint8
+	Status             uint8
+	LpMintDecimals     uint8
+	Mint0Decimals      uint8
+	Mint1Decimals      uint8
+	LpSupply           uint64
+	ProtocolFeesToken0 uint64
+	ProtocolFeesToken1 uint64
+	FundFeesToken0     uint64
+	FundFeesToken1     uint64
+	OpenTime           uint64
+	RecentEpoch        uint64
+	Padding            [31]uint64
+}
+
+// RaydiumCpmmCoder decodes Raydium CPMM instructions and pool state.
+type RaydiumCpmmCoder struct{}
+
+func NewRaydiumCpmmCoder() *RaydiumCpmmCoder {
+	return &RaydiumCpmmCoder{}
+}
+
+// Decode decodes the given byte array into an instruction.
+func (coder *RaydiumCpmmCoder) Decode(data []byte) (interface{}, error) {
+	decoded, err := decodeCpmm(data)
+	if err != nil {
+		recordDecodeFailure(PROGRAM_RAYDIUM_CPMM, err)
+	}
+	return decoded, err
+}
+
+func (coder *RaydiumCpmmCoder) DecodePoolState(data []byte) (CpmmPoolState, error) {
+	var state CpmmPoolState
+	err := decodeAnchorAccount("CpmmPoolState", cpmmPoolState, data, &state)
+	return state, err
+}
+
+func decodeCpmm(data []byte) (interface{}, error) {
+	buf, discriminator, err := readDiscriminator(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch discriminator {
+	case cpmmInitialize:
+		var instruction CpmmInitialize
+		if err := expectLength("CpmmInitialize", buf, 24); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction)
+	case cpmmDeposit:
+		var instruction CpmmDeposit
+		if err := expectLength("CpmmDeposit", buf, 24); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction)
+	case cpmmWithdraw:
+		var instruction CpmmWithdraw
+		if err := expectLength("CpmmWithdraw", buf, 24); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction)
+	case cpmmSwapBaseInput:
+		var instruction CpmmSwapBaseInput
+		if err := expectLength("CpmmSwapBaseInput", buf, 16); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction)
+	case cpmmSwapBaseOutput:
+		var instruction CpmmSwapBaseOutput
+		if err := expectLength("CpmmSwapBaseOutput", buf, 16); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction)
+	default:
+		return nil, unknownDiscriminator(discriminator)
+	}
+}
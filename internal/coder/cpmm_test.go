@@ -0,0 +1,88 @@
+package coder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRaydiumCpmmDecode(t *testing.T) {
+	runDecodeTests(t, NewRaydiumCpmmCoder(), []decodeTest{
+		{
+			name: "initialize",
+			data: append(disc("afaf6d1f0d989bed"), le(uint64(1_000), uint64(2_000), uint64(1_700_000_000))...),
+			want: CpmmInitialize{InitAmount0: 1_000, InitAmount1: 2_000, OpenTime: 1_700_000_000},
+		},
+		{
+			name: "deposit",
+			data: append(disc("f223c68952e1f2b6"), le(uint64(50), uint64(60), uint64(70))...),
+			want: CpmmDeposit{LpTokenAmount: 50, MaximumToken0Amount: 60, MaximumToken1Amount: 70},
+		},
+		{
+			name: "withdraw",
+			data: append(disc("b712469c946da122"), le(uint64(50), uint64(1), uint64(2))...),
+			want: CpmmWithdraw{LpTokenAmount: 50, MinimumToken0Amount: 1, MinimumToken1Amount: 2},
+		},
+		{
+			name: "swap_base_input",
+			data: append(disc("8fbe5adac41e33de"), le(uint64(1_000_000), uint64(900))...),
+			want: CpmmSwapBaseInput{AmountIn: 1_000_000, MinimumAmountOut: 900},
+		},
+		{
+			name: "swap_base_output",
+			data: append(disc("37d96256a34ab4ad"), le(uint64(1_100), uint64(1_000))...),
+			want: CpmmSwapBaseOutput{MaxAmountIn: 1_100, AmountOut: 1_000},
+		},
+		{name: "short discriminator", data: []byte{0xaf, 0xaf, 0x6d}, wantErr: ErrShortData},
+		{name: "short withdraw", data: append(disc("b712469c946da122"), le(uint64(50))...), wantErr: ErrShortData},
+		{name: "long swap", data: append(disc("8fbe5adac41e33de"), le(uint64(1), uint64(2), uint8(0))...), wantErr: ErrTrailingData},
+		{name: "unknown discriminator", data: append(disc("0000000000000000"), le(uint64(1), uint64(2))...), wantErr: ErrUnknownDiscriminator},
+	})
+}
+
+func TestRaydiumCpmmDecodePoolState(t *testing.T) {
+	var (
+		vault0 = testKey(10)
+		lpMint = testKey(20)
+		mint0  = testKey(30)
+		mint1  = testKey(40)
+	)
+
+	// Offsets of the on-chain PoolState, which is 637 bytes with its padding
+	data := account(637, map[int]interface{}{
+		0:   disc("f7ede3f5d7c3de46"),
+		72:  vault0,
+		136: lpMint,
+		168: mint0,
+		200: mint1,
+		330: uint8(9),
+		331: uint8(9),
+		332: uint8(6),
+		333: uint64(123_456_789),
+		373: uint64(1_700_000_000),
+	})
+
+	state, err := NewRaydiumCpmmCoder().DecodePoolState(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Token0Vault != vault0 || state.LpMint != lpMint || state.Token0Mint != mint0 || state.Token1Mint != mint1 {
+		t.Errorf("keys = %s %s %s %s, want %s %s %s %s",
+			state.Token0Vault, state.LpMint, state.Token0Mint, state.Token1Mint, vault0, lpMint, mint0, mint1)
+	}
+	if state.LpMintDecimals != 9 || state.Mint0Decimals != 9 || state.Mint1Decimals != 6 {
+		t.Errorf("decimals = %d %d %d, want 9 9 6", state.LpMintDecimals, state.Mint0Decimals, state.Mint1Decimals)
+	}
+	if state.LpSupply != 123_456_789 || state.OpenTime != 1_700_000_000 {
+		t.Errorf("lp supply = %d, open time = %d", state.LpSupply, state.OpenTime)
+	}
+
+	if _, err := NewRaydiumCpmmCoder().DecodePoolState(data[:300]); !errors.Is(err, ErrShortData) {
+		t.Errorf("truncated account: err = %v, want %v", err, ErrShortData)
+	}
+
+	data[0] ^= 0xff
+	if _, err := NewRaydiumCpmmCoder().DecodePoolState(data); !errors.Is(err, ErrUnknownDiscriminator) {
+		t.Errorf("wrong discriminator: err = %v, want %v", err, ErrUnknownDiscriminator)
+	}
+}
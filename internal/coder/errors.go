@@ -0,0 +1,110 @@
+package coder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+var (
+	ErrShortData            = errors.New("data too short")
+	ErrTrailingData         = errors.New("unexpected trailing data")
+	ErrUnknownDiscriminator = errors.New("unknown instruction discriminator")
+	ErrUnknownProgram       = errors.New("no decoder registered for program")
+)
+
+// Program names used for decode failure counters
+const (
+	PROGRAM_RAYDIUM_AMM_V4 = "raydium_amm_v4"
+	PROGRAM_COMPUTE_BUDGET = "compute_budget"
+	PROGRAM_SYSTEM         = "system"
+	PROGRAM_RAYDIUM_CPMM   = "raydium_cpmm"
+	PROGRAM_RAYDIUM_CLMM   = "raydium_clmm"
+	PROGRAM_PUMP_FUN       = "pump_fun"
+	PROGRAM_METEORA_DLMM   = "meteora_dlmm"
+	PROGRAM_METEORA_DAMM   = "meteora_damm"
+	PROGRAM_ORCA_WHIRLPOOL = "orca_whirlpool"
+	PROGRAM_SPL_TOKEN      = "spl_token"
+	PROGRAM_TOKEN_2022     = "token_2022"
+)
+
+var (
+	decodeFailuresMutex sync.Mutex
+	decodeFailures      = make(map[string]map[string]uint64)
+)
+
+// expectLength checks that the unread part of buf is exactly one of sizes.
+func expectLength(name string, buf *bytes.Reader, sizes ...int) error {
+	remaining := buf.Len()
+	longest := 0
+
+	for _, size := range sizes {
+		if remaining == size {
+			return nil
+		}
+		longest = max(longest, size)
+	}
+
+	if remaining > longest {
+		return fmt.Errorf("%w: %s expects %v bytes, got %d", ErrTrailingData, name, sizes, remaining)
+	}
+	return fmt.Errorf("%w: %s expects %v bytes, got %d", ErrShortData, name, sizes, remaining)
+}
+
+// read decodes each field in order, mapping a premature end of data to ErrShortData.
+func read(buf *bytes.Reader, fields ...interface{}) error {
+	for _, field := range fields {
+		if err := binary.Read(buf, binary.LittleEndian, field); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return ErrShortData
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func unknownDiscriminator(discriminator interface{}) error {
+	return fmt.Errorf("%w: %v", ErrUnknownDiscriminator, discriminator)
+}
+
+// recordDecodeFailure counts a failed decode for program, keyed by the kind of failure.
+func recordDecodeFailure(program string, err error) {
+	var reason string
+	switch {
+	case errors.Is(err, ErrShortData):
+		reason = "short_data"
+	case errors.Is(err, ErrTrailingData):
+		reason = "trailing_data"
+	case errors.Is(err, ErrUnknownDiscriminator):
+		reason = "unknown_discriminator"
+	default:
+		reason = "other"
+	}
+
+	decodeFailuresMutex.Lock()
+	defer decodeFailuresMutex.Unlock()
+
+	if decodeFailures[program] == nil {
+		decodeFailures[program] = make(map[string]uint64)
+	}
+	decodeFailures[program][reason]++
+}
+
+// DecodeFailures returns a copy of the failure counters per program and reason.
+func DecodeFailures() map[string]map[string]uint64 {
+	decodeFailuresMutex.Lock()
+	defer decodeFailuresMutex.Unlock()
+
+	failures := make(map[string]map[string]uint64, len(decodeFailures))
+	for program, reasons := range decodeFailures {
+		failures[program] = make(map[string]uint64, len(reasons))
+		for reason, count := range reasons {
+			failures[program][reason] = count
+		}
+	}
+	return failures
+}
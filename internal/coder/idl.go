@@ -0,0 +1,535 @@
+package coder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Idl is an Anchor IDL. Both the legacy format and the format of Anchor 0.30,
+// which carries explicit discriminators, are accepted.
+type Idl struct {
+	Address      string           `json:"address"`
+	Name         string           `json:"name"`
+	Metadata     IdlMetadata      `json:"metadata"`
+	Instructions []IdlInstruction `json:"instructions"`
+	Types        []IdlTypeDef     `json:"types"`
+}
+
+type IdlMetadata struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+type IdlInstruction struct {
+	Name          string           `json:"name"`
+	Discriminator []int            `json:"discriminator"`
+	Accounts      []IdlAccountItem `json:"accounts"`
+	Args          IdlFields        `json:"args"`
+}
+
+// IdlAccountItem is an account of an instruction, or a group of accounts in the
+// legacy format.
+type IdlAccountItem struct {
+	Name     string           `json:"name"`
+	Accounts []IdlAccountItem `json:"accounts"`
+}
+
+type IdlTypeDef struct {
+	Name string         `json:"name"`
+	Type IdlTypeDefBody `json:"type"`
+}
+
+// IdlTypeDefBody is a struct when Kind is "struct" and an enum when Kind is "enum".
+type IdlTypeDefBody struct {
+	Kind     string           `json:"kind"`
+	Fields   IdlFields        `json:"fields"`
+	Variants []IdlEnumVariant `json:"variants"`
+}
+
+type IdlEnumVariant struct {
+	Name   string    `json:"name"`
+	Fields IdlFields `json:"fields"`
+}
+
+type IdlField struct {
+	Name string  `json:"name"`
+	Type IdlType `json:"type"`
+}
+
+// IdlFields are named fields, or the fields of a tuple which are named by their
+// position.
+type IdlFields []IdlField
+
+// IdlType is one of a primitive, a vec, an option, a fixed size array or a type
+// defined in the IDL.
+type IdlType struct {
+	Primitive string
+	Vec       *IdlType
+	Option    *IdlType
+	Array     *IdlType
+	Length    int
+	Defined   string
+}
+
+// AnchorInstruction is an instruction decoded from an IDL. Accounts are the names
+// of the instruction accounts in order.
+type AnchorInstruction struct {
+	Program  string
+	Name     string
+	Args     map[string]interface{}
+	Accounts []string
+}
+
+// AnchorIdlCoder decodes the instructions of any Anchor program from its IDL.
+type AnchorIdlCoder struct {
+	program      string
+	instructions map[[8]byte]*IdlInstruction
+	types        map[string]*IdlTypeDef
+}
+
+// LoadAnchorIdl reads an IDL JSON file.
+func LoadAnchorIdl(path string) (*Idl, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var idl Idl
+	if err := json.Unmarshal(data, &idl); err != nil {
+		return nil, fmt.Errorf("failed to parse IDL %s: %w", path, err)
+	}
+	return &idl, nil
+}
+
+func NewAnchorIdlCoder(idl *Idl) (*AnchorIdlCoder, error) {
+	coder := &AnchorIdlCoder{
+		program:      idl.ProgramName(),
+		instructions: make(map[[8]byte]*IdlInstruction, len(idl.Instructions)),
+		types:        make(map[string]*IdlTypeDef, len(idl.Types)),
+	}
+
+	for i := range idl.Types {
+		coder.types[idl.Types[i].Name] = &idl.Types[i]
+	}
+
+	// Only the types used by instructions have to be supported
+	checked := make(map[string]bool)
+
+	for i := range idl.Instructions {
+		instruction := &idl.Instructions[i]
+
+		discriminator, err := instruction.discriminator()
+		if err != nil {
+			return nil, err
+		}
+
+		if existing, ok := coder.instructions[discriminator]; ok {
+			return nil, fmt.Errorf("instructions %s and %s have the same discriminator", existing.Name, instruction.Name)
+		}
+		coder.instructions[discriminator] = instruction
+
+		if err := coder.checkFields(instruction.Args, checked); err != nil {
+			return nil, fmt.Errorf("instruction %s: %w", instruction.Name, err)
+		}
+	}
+
+	return coder, nil
+}
+
+// ProgramName returns the program name from either IDL format.
+func (idl *Idl) ProgramName() string {
+	if idl.Metadata.Name != "" {
+		return idl.Metadata.Name
+	}
+	return idl.Name
+}
+
+// ProgramAddress returns the program ID the IDL names, if any.
+func (idl *Idl) ProgramAddress() string {
+	if idl.Address != "" {
+		return idl.Address
+	}
+	return idl.Metadata.Address
+}
+
+// Decode decodes the given byte array into an AnchorInstruction.
+func (coder *AnchorIdlCoder) Decode(data []byte) (interface{}, error) {
+	decoded, err := coder.decode(data)
+	if err != nil {
+		recordDecodeFailure(coder.program, err)
+	}
+	return decoded, err
+}
+
+func (coder *AnchorIdlCoder) decode(data []byte) (interface{}, error) {
+	buf, discriminator, err := readDiscriminator(data)
+	if err != nil {
+		return nil, err
+	}
+
+	instruction, ok := coder.instructions[discriminator]
+	if !ok {
+		return nil, unknownDiscriminator(discriminator)
+	}
+
+	args, err := coder.readFields(buf, instruction.Args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", instruction.Name, err)
+	}
+
+	if buf.Len() > 0 {
+		return nil, fmt.Errorf("%w: %s has %d bytes left", ErrTrailingData, instruction.Name, buf.Len())
+	}
+
+	return AnchorInstruction{
+		Program:  coder.program,
+		Name:     instruction.Name,
+		Args:     args,
+		Accounts: flattenAccounts(instruction.Accounts),
+	}, nil
+}
+
+func (coder *AnchorIdlCoder) readFields(buf *bytes.Reader, fields IdlFields) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		value, err := coder.readValue(buf, field.Type)
+		if err != nil {
+			return nil, err
+		}
+		values[field.Name] = value
+	}
+	return values, nil
+}
+
+func (coder *AnchorIdlCoder) readValue(buf *bytes.Reader, idlType IdlType) (interface{}, error) {
+	switch {
+	case idlType.Vec != nil:
+		var length uint32
+		if err := read(buf, &length); err != nil {
+			return nil, err
+		}
+
+		if int64(length) > int64(buf.Len()) {
+			return nil, fmt.Errorf("%w: vec of %d items, %d bytes left", ErrShortData, length, buf.Len())
+		}
+		return coder.readItems(buf, *idlType.Vec, int(length))
+	case idlType.Array != nil:
+		return coder.readItems(buf, *idlType.Array, idlType.Length)
+	case idlType.Option != nil:
+		var present uint8
+		if err := read(buf, &present); err != nil {
+			return nil, err
+		}
+
+		if present == 0 {
+			return nil, nil
+		}
+		return coder.readValue(buf, *idlType.Option)
+	case idlType.Defined != "":
+		return coder.readDefined(buf, idlType.Defined)
+	default:
+		return readPrimitive(buf, idlType.Primitive)
+	}
+}
+
+func (coder *AnchorIdlCoder) readItems(buf *bytes.Reader, idlType IdlType, length int) ([]interface{}, error) {
+	items := make([]interface{}, length)
+	for i := range items {
+		item, err := coder.readValue(buf, idlType)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+// readDefined reads a struct as a map of its fields. An enum variant without fields
+// is read as its name, and one with fields as a map of the name to its fields.
+func (coder *AnchorIdlCoder) readDefined(buf *bytes.Reader, name string) (interface{}, error) {
+	typeDef := coder.types[name]
+
+	if typeDef.Type.Kind != "enum" {
+		return coder.readFields(buf, typeDef.Type.Fields)
+	}
+
+	var variantIndex uint8
+	if err := read(buf, &variantIndex); err != nil {
+		return nil, err
+	}
+
+	if int(variantIndex) >= len(typeDef.Type.Variants) {
+		return nil, fmt.Errorf("%w: variant %d of %s", ErrUnknownDiscriminator, variantIndex, name)
+	}
+
+	variant := typeDef.Type.Variants[variantIndex]
+	if len(variant.Fields) == 0 {
+		return variant.Name, nil
+	}
+
+	fields, err := coder.readFields(buf, variant.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{variant.Name: fields}, nil
+}
+
+func readPrimitive(buf *bytes.Reader, primitive string) (interface{}, error) {
+	switch primitive {
+	case "bool":
+		return readNumber[bool](buf)
+	case "u8":
+		return readNumber[uint8](buf)
+	case "i8":
+		return readNumber[int8](buf)
+	case "u16":
+		return readNumber[uint16](buf)
+	case "i16":
+		return readNumber[int16](buf)
+	case "u32":
+		return readNumber[uint32](buf)
+	case "i32":
+		return readNumber[int32](buf)
+	case "u64":
+		return readNumber[uint64](buf)
+	case "i64":
+		return readNumber[int64](buf)
+	case "f32":
+		return readNumber[float32](buf)
+	case "f64":
+		return readNumber[float64](buf)
+	case "u128", "i128":
+		var integer Uint128
+		if err := read(buf, &integer); err != nil {
+			return nil, err
+		}
+
+		number := integer.BigInt()
+		if primitive == "i128" && integer.Hi>>63 == 1 {
+			number.Sub(number, new(big.Int).Lsh(big.NewInt(1), 128))
+		}
+		return number, nil
+	case "string":
+		return readBorshString(buf)
+	case "bytes":
+		value, err := readBorshString(buf)
+		return []byte(value), err
+	case "publicKey", "pubkey":
+		return readNumber[solana.PublicKey](buf)
+	default:
+		return nil, fmt.Errorf("unsupported IDL type %q", primitive)
+	}
+}
+
+// readNumber reads a fixed size value of type T.
+func readNumber[T any](buf *bytes.Reader) (interface{}, error) {
+	var value T
+	err := read(buf, &value)
+	return value, err
+}
+
+// checkTypeDef checks the fields of a struct or the variants of an enum.
+func (coder *AnchorIdlCoder) checkTypeDef(typeDef *IdlTypeDef, checked map[string]bool) error {
+	switch typeDef.Type.Kind {
+	case "struct":
+		return coder.checkFields(typeDef.Type.Fields, checked)
+	case "enum":
+		for _, variant := range typeDef.Type.Variants {
+			if err := coder.checkFields(variant.Fields, checked); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported kind %q", typeDef.Type.Kind)
+	}
+}
+
+func (coder *AnchorIdlCoder) checkFields(fields IdlFields, checked map[string]bool) error {
+	for _, field := range fields {
+		if err := coder.checkType(field.Type, checked); err != nil {
+			return fmt.Errorf("%s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// checkType makes sure a type is supported and refers only to defined types, so
+// that decoding only fails on bad data. Each defined type is checked once, which
+// also stops types that refer to themselves.
+func (coder *AnchorIdlCoder) checkType(idlType IdlType, checked map[string]bool) error {
+	switch {
+	case idlType.Vec != nil:
+		return coder.checkType(*idlType.Vec, checked)
+	case idlType.Array != nil:
+		return coder.checkType(*idlType.Array, checked)
+	case idlType.Option != nil:
+		return coder.checkType(*idlType.Option, checked)
+	case idlType.Defined != "":
+		typeDef, ok := coder.types[idlType.Defined]
+		if !ok {
+			return fmt.Errorf("type %s is not defined", idlType.Defined)
+		}
+
+		if checked[typeDef.Name] {
+			return nil
+		}
+		checked[typeDef.Name] = true
+
+		if err := coder.checkTypeDef(typeDef, checked); err != nil {
+			return fmt.Errorf("type %s: %w", typeDef.Name, err)
+		}
+		return nil
+	}
+
+	switch idlType.Primitive {
+	case "bool", "u8", "i8", "u16", "i16", "u32", "i32", "u64", "i64", "f32", "f64",
+		"u128", "i128", "string", "bytes", "publicKey", "pubkey":
+		return nil
+	}
+	return fmt.Errorf("unsupported IDL type %q", idlType.Primitive)
+}
+
+// discriminator returns the explicit discriminator of the instruction, or the one
+// Anchor derives from its snake case name for IDLs without one.
+func (instruction *IdlInstruction) discriminator() ([8]byte, error) {
+	var discriminator [8]byte
+
+	if len(instruction.Discriminator) == 0 {
+		return AnchorDiscriminator(toSnakeCase(instruction.Name)), nil
+	}
+
+	if len(instruction.Discriminator) != len(discriminator) {
+		return discriminator, fmt.Errorf("%s: discriminator has %d bytes", instruction.Name, len(instruction.Discriminator))
+	}
+
+	for i, value := range instruction.Discriminator {
+		if value < 0 || value > 255 {
+			return discriminator, fmt.Errorf("%s: discriminator byte %d is out of range", instruction.Name, value)
+		}
+		discriminator[i] = byte(value)
+	}
+	return discriminator, nil
+}
+
+func flattenAccounts(items []IdlAccountItem) []string {
+	var names []string
+	for _, item := range items {
+		if len(item.Accounts) > 0 {
+			names = append(names, flattenAccounts(item.Accounts)...)
+			continue
+		}
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+// toSnakeCase converts the camel case names of legacy IDLs, e.g. swapBaseInput to
+// swap_base_input.
+func toSnakeCase(name string) string {
+	var builder strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				builder.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// UnmarshalJSON accepts named fields as well as the bare types of a tuple.
+func (fields *IdlFields) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	*fields = make(IdlFields, len(items))
+	for i, item := range items {
+		var field struct {
+			Name string   `json:"name"`
+			Type *IdlType `json:"type"`
+		}
+
+		if err := json.Unmarshal(item, &field); err == nil && field.Name != "" && field.Type != nil {
+			(*fields)[i] = IdlField{Name: field.Name, Type: *field.Type}
+			continue
+		}
+
+		var idlType IdlType
+		if err := json.Unmarshal(item, &idlType); err != nil {
+			return err
+		}
+		(*fields)[i] = IdlField{Name: strconv.Itoa(i), Type: idlType}
+	}
+	return nil
+}
+
+func (idlType *IdlType) UnmarshalJSON(data []byte) error {
+	var primitive string
+	if err := json.Unmarshal(data, &primitive); err == nil {
+		idlType.Primitive = primitive
+		return nil
+	}
+
+	var composite struct {
+		Vec     *IdlType          `json:"vec"`
+		Option  *IdlType          `json:"option"`
+		COption *IdlType          `json:"coption"`
+		Array   []json.RawMessage `json:"array"`
+		Defined json.RawMessage   `json:"defined"`
+	}
+
+	if err := json.Unmarshal(data, &composite); err != nil {
+		return err
+	}
+
+	switch {
+	case composite.Vec != nil:
+		idlType.Vec = composite.Vec
+	case composite.Option != nil:
+		idlType.Option = composite.Option
+	case composite.COption != nil:
+		return errors.New("unsupported IDL type coption")
+	case composite.Array != nil:
+		if len(composite.Array) != 2 {
+			return fmt.Errorf("invalid IDL array %s", data)
+		}
+
+		idlType.Array = new(IdlType)
+		if err := json.Unmarshal(composite.Array[0], idlType.Array); err != nil {
+			return err
+		}
+
+		if err := json.Unmarshal(composite.Array[1], &idlType.Length); err != nil {
+			return fmt.Errorf("unsupported IDL array length %s", composite.Array[1])
+		}
+	case composite.Defined != nil:
+		// Legacy IDLs name the type directly, newer ones wrap it in an object
+		if err := json.Unmarshal(composite.Defined, &idlType.Defined); err != nil {
+			var defined struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(composite.Defined, &defined); err != nil {
+				return err
+			}
+			idlType.Defined = defined.Name
+		}
+	default:
+		return fmt.Errorf("unsupported IDL type %s", data)
+	}
+
+	return nil
+}
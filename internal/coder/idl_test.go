@@ -0,0 +1,137 @@
+package coder
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// testIdl is a made up program in the Anchor 0.30 format, with one instruction
+// carrying its discriminator and one left to be derived from its name.
+const testIdl = `{
+  "address": "Prog1111111111111111111111111111111111111111",
+  "metadata": {"name": "test_program"},
+  "instructions": [
+    {
+      "name": "place_order",
+      "discriminator": [1, 2, 3, 4, 5, 6, 7, 8],
+      "accounts": [{"name": "user"}, {"name": "market", "accounts": [{"name": "book"}, {"name": "vault"}]}],
+      "args": [
+        {"name": "params", "type": {"defined": {"name": "OrderParams"}}},
+        {"name": "side", "type": {"defined": {"name": "Side"}}},
+        {"name": "memo", "type": {"option": "string"}},
+        {"name": "owners", "type": {"vec": "pubkey"}},
+        {"name": "seed", "type": {"array": ["u8", 2]}}
+      ]
+    },
+    {
+      "name": "cancelAll",
+      "accounts": [{"name": "user"}],
+      "args": [{"name": "delta", "type": "i128"}]
+    }
+  ],
+  "types": [
+    {"name": "OrderParams", "type": {"kind": "struct", "fields": [{"name": "price", "type": "u64"}, {"name": "size", "type": "u32"}]}},
+    {"name": "Side", "type": {"kind": "enum", "variants": [{"name": "Bid"}, {"name": "Ask", "fields": ["u8"]}]}}
+  ]
+}`
+
+func newTestIdlCoder(t *testing.T, source string) (*AnchorIdlCoder, error) {
+	t.Helper()
+
+	var idl Idl
+	if err := json.Unmarshal([]byte(source), &idl); err != nil {
+		t.Fatal(err)
+	}
+	return NewAnchorIdlCoder(&idl)
+}
+
+func TestAnchorIdlDecode(t *testing.T) {
+	coder, err := newTestIdlCoder(t, testIdl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owner := testKey(1)
+	placeOrder := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	// global:cancel_all, derived from the camel case name
+	cancelAll := disc("62bf4bdc732847ed")
+
+	placeOrderArgs := le(uint64(1_500), uint32(3), uint8(1), uint8(9), uint8(1))
+	placeOrderArgs = append(placeOrderArgs, borshString("gm")...)
+	placeOrderArgs = append(placeOrderArgs, le(uint32(1), owner, [2]byte{7, 8})...)
+
+	runDecodeTests(t, coder, []decodeTest{
+		{
+			name: "explicit discriminator",
+			data: append(placeOrder, placeOrderArgs...),
+			want: AnchorInstruction{
+				Program: "test_program",
+				Name:    "place_order",
+				Args: map[string]interface{}{
+					"params": map[string]interface{}{"price": uint64(1_500), "size": uint32(3)},
+					"side":   map[string]interface{}{"Ask": map[string]interface{}{"0": uint8(9)}},
+					"memo":   "gm",
+					"owners": []interface{}{owner},
+					"seed":   []interface{}{uint8(7), uint8(8)},
+				},
+				Accounts: []string{"user", "book", "vault"},
+			},
+		},
+		{
+			name: "derived discriminator",
+			data: append(append([]byte{}, cancelAll...), le(^uint64(0), ^uint64(0))...),
+			want: AnchorInstruction{
+				Program:  "test_program",
+				Name:     "cancelAll",
+				Args:     map[string]interface{}{"delta": big.NewInt(-1)},
+				Accounts: []string{"user"},
+			},
+		},
+		{name: "short discriminator", data: placeOrder[:4], wantErr: ErrShortData},
+		{name: "short args", data: append(placeOrder, le(uint64(1_500))...), wantErr: ErrShortData},
+		{name: "vec longer than the data", data: append(placeOrder, append(le(uint64(1), uint32(1), uint8(0), uint8(0)), le(uint32(5))...)...), wantErr: ErrShortData},
+		{name: "unknown enum variant", data: append(placeOrder, le(uint64(1), uint32(1), uint8(2))...), wantErr: ErrUnknownDiscriminator},
+		{name: "trailing data", data: append(append([]byte{}, cancelAll...), le(uint64(0), uint64(0), uint8(0))...), wantErr: ErrTrailingData},
+		{name: "unknown discriminator", data: disc("0000000000000000"), wantErr: ErrUnknownDiscriminator},
+	})
+}
+
+func TestNewAnchorIdlCoderRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		idl  string
+		want string
+	}{
+		{
+			name: "undefined type",
+			idl:  `{"name": "p", "instructions": [{"name": "a", "args": [{"name": "x", "type": {"defined": "Missing"}}]}]}`,
+			want: "type Missing is not defined",
+		},
+		{
+			name: "unsupported primitive",
+			idl:  `{"name": "p", "instructions": [{"name": "a", "args": [{"name": "x", "type": "u256"}]}]}`,
+			want: `unsupported IDL type "u256"`,
+		},
+		{
+			name: "short discriminator",
+			idl:  `{"name": "p", "instructions": [{"name": "a", "discriminator": [1, 2, 3], "args": []}]}`,
+			want: "discriminator has 3 bytes",
+		},
+		{
+			name: "duplicate discriminator",
+			idl:  `{"name": "p", "instructions": [{"name": "a", "discriminator": [1, 1, 1, 1, 1, 1, 1, 1], "args": []}, {"name": "b", "discriminator": [1, 1, 1, 1, 1, 1, 1, 1], "args": []}]}`,
+			want: "instructions a and b have the same discriminator",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTestIdlCoder(t, tt.idl)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewAnchorIdlCoder() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -2,8 +2,37 @@ package coder
 
 import (
 	"bytes"
-	"encoding/binary"
-	"errors"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// SetParams param values from the AMM v4 program
+const (
+	PARAM_STATUS               = 0
+	PARAM_STATE                = 1
+	PARAM_ORDER_NUM            = 2
+	PARAM_DEPTH                = 3
+	PARAM_AMOUNT_WAVE          = 4
+	PARAM_MIN_PRICE_MULTIPLIER = 5
+	PARAM_MAX_PRICE_MULTIPLIER = 6
+	PARAM_MIN_SIZE             = 7
+	PARAM_VOL_MAX_CUT_RATIO    = 8
+	PARAM_FEES                 = 9
+	PARAM_AMM_OWNER            = 10
+	PARAM_SET_OPEN_TIME        = 11
+	PARAM_LAST_ORDER_DISTANCE  = 12
+	PARAM_INIT_ORDER_DEPTH     = 13
+	PARAM_SET_SWITCH_TIME      = 14
+	PARAM_CLEAR_OPEN_TIME      = 15
+	PARAM_SEPERATE             = 16
+	PARAM_UPDATE_OPEN_ORDER    = 17
+)
+
+// Account positions in Initialize2
+const (
+	AMM_INITIALIZE_POOL      = 4
+	AMM_INITIALIZE_COIN_MINT = 8
+	AMM_INITIALIZE_PC_MINT   = 9
 )
 
 // RaydiumAmmInstructionCoder implements the Coder interface.
@@ -15,85 +44,264 @@ func NewRaydiumAmmInstructionCoder() *RaydiumAmmInstructionCoder {
 
 // Decode decodes the given byte array into an instruction.
 func (coder *RaydiumAmmInstructionCoder) Decode(data []byte) (interface{}, error) {
-	return decodeData(data)
-}
-
-func (coder *RaydiumAmmInstructionCoder) DecodeCompute(data []byte) (Compute, error) {
-	return decodeCompute(data)
-}
-
-func (coder *RaydiumAmmInstructionCoder) DecodeTransfer(data []byte) (Transfer, error) {
-	return decodeTransfer(data)
+	decoded, err := decodeData(data)
+	if err != nil {
+		recordDecodeFailure(PROGRAM_RAYDIUM_AMM_V4, err)
+	}
+	return decoded, err
 }
 
 // Decoding function.
 func decodeData(data []byte) (interface{}, error) {
 	buf := bytes.NewReader(data)
 	var instructionID byte
-	binary.Read(buf, binary.LittleEndian, &instructionID)
+	if err := read(buf, &instructionID); err != nil {
+		return nil, err
+	}
 
 	switch instructionID {
+	case 0:
+		return decodeInitialize(buf)
 	case 1:
 		return decodeInitialize2(buf)
+	case 2:
+		return decodeMonitorStep(buf)
+	case 3:
+		return decodeDeposit(buf)
 	case 4:
 		return decodeWithdraw(buf)
+	case 5:
+		return MigrateToOpenBook{}, expectLength("MigrateToOpenBook", buf, 0)
+	case 6:
+		return decodeSetParams(buf)
+	case 7:
+		return WithdrawPnl{}, expectLength("WithdrawPnl", buf, 0)
+	case 8:
+		return decodeWithdrawSrm(buf)
 	case 9:
 		return decodeSwapBaseIn(buf)
+	case 10:
+		return decodePreInitialize(buf)
 	case 11:
 		return decodeSwapBaseOut(buf)
+	case 12:
+		return decodeSimulateInfo(buf)
+	case 13:
+		return decodeAdminCancelOrders(buf)
+	case 14:
+		return CreateConfigAccount{}, expectLength("CreateConfigAccount", buf, 0)
+	case 15:
+		return decodeUpdateConfigAccount(buf)
 	default:
-		return nil, errors.New("invalid instruction ID")
+		return nil, unknownDiscriminator(instructionID)
 	}
 }
 
-func decodeCompute(data []byte) (Compute, error) {
-	var instruction Compute
+func decodeInitialize(buf *bytes.Reader) (Initialize, error) {
+	var instruction Initialize
+	if err := expectLength("Initialize", buf, 9); err != nil {
+		return instruction, err
+	}
 
-	buf := bytes.NewReader(data)
-	binary.Read(buf, binary.LittleEndian, &instruction.Instruction)
-	binary.Read(buf, binary.LittleEndian, &instruction.Value)
+	err := read(buf, &instruction.Nonce, &instruction.OpenTime)
+	return instruction, err
+}
 
-	return instruction, nil
+func decodeInitialize2(buf *bytes.Reader) (Initialize2, error) {
+	var instruction Initialize2
+	if err := expectLength("Initialize2", buf, 25); err != nil {
+		return instruction, err
+	}
+
+	err := read(buf, &instruction.Nonce, &instruction.OpenTime, &instruction.InitPcAmount, &instruction.InitCoinAmount)
+	return instruction, err
 }
 
-func decodeTransfer(data []byte) (Transfer, error) {
-	var instruction Transfer
+func decodeMonitorStep(buf *bytes.Reader) (MonitorStep, error) {
+	var instruction MonitorStep
+	if err := expectLength("MonitorStep", buf, 6); err != nil {
+		return instruction, err
+	}
 
-	buf := bytes.NewReader(data)
-	binary.Read(buf, binary.LittleEndian, &instruction.Instruction)
-	binary.Read(buf, binary.LittleEndian, &instruction.Amount)
-	return instruction, nil
+	err := read(buf, &instruction.PlanOrderLimit, &instruction.PlaceOrderLimit, &instruction.CancelOrderLimit)
+	return instruction, err
 }
 
-func decodeInitialize2(buf *bytes.Reader) (Initialize2, error) {
-	var instruction Initialize2
-	binary.Read(buf, binary.LittleEndian, &instruction.Nonce)
-	binary.Read(buf, binary.LittleEndian, &instruction.OpenTime)
-	binary.Read(buf, binary.LittleEndian, &instruction.InitPcAmount)
-	binary.Read(buf, binary.LittleEndian, &instruction.InitCoinAmount)
+func decodeDeposit(buf *bytes.Reader) (Deposit, error) {
+	var instruction Deposit
+
+	// Older clients omit the minimum amount of the other side
+	if err := expectLength("Deposit", buf, 24, 32); err != nil {
+		return instruction, err
+	}
+
+	if err := read(buf, &instruction.MaxCoinAmount, &instruction.MaxPcAmount, &instruction.BaseSide); err != nil {
+		return instruction, err
+	}
+
+	if buf.Len() > 0 {
+		instruction.OtherAmountMin = new(uint64)
+		if err := read(buf, instruction.OtherAmountMin); err != nil {
+			return instruction, err
+		}
+	}
 
 	return instruction, nil
 }
 
 func decodeWithdraw(buf *bytes.Reader) (Withdraw, error) {
 	var instruction Withdraw
-	binary.Read(buf, binary.LittleEndian, &instruction.Amount)
+
+	// Older clients omit the minimum amounts out
+	if err := expectLength("Withdraw", buf, 8, 24); err != nil {
+		return instruction, err
+	}
+
+	if err := read(buf, &instruction.Amount); err != nil {
+		return instruction, err
+	}
+
+	if buf.Len() > 0 {
+		instruction.MinCoinAmount = new(uint64)
+		instruction.MinPcAmount = new(uint64)
+		if err := read(buf, instruction.MinCoinAmount, instruction.MinPcAmount); err != nil {
+			return instruction, err
+		}
+	}
 
 	return instruction, nil
 }
 
+func decodeSetParams(buf *bytes.Reader) (SetParams, error) {
+	var instruction SetParams
+	if err := read(buf, &instruction.Param); err != nil {
+		return instruction, err
+	}
+
+	var err error
+	switch instruction.Param {
+	case PARAM_AMM_OWNER:
+		if err = expectLength("SetParams", buf, 32); err == nil {
+			instruction.NewPubkey = new(solana.PublicKey)
+			err = read(buf, instruction.NewPubkey)
+		}
+	case PARAM_FEES:
+		if err = expectLength("SetParams", buf, 64); err == nil {
+			instruction.Fees = new(AmmFees)
+			err = read(buf, instruction.Fees)
+		}
+	case PARAM_LAST_ORDER_DISTANCE:
+		if err = expectLength("SetParams", buf, 16); err == nil {
+			instruction.LastOrderDistance = new(LastOrderDistance)
+			err = read(buf, instruction.LastOrderDistance)
+		}
+	default:
+		if err = expectLength("SetParams", buf, 0, 8); err == nil && buf.Len() > 0 {
+			instruction.Value = new(uint64)
+			err = read(buf, instruction.Value)
+		}
+	}
+
+	return instruction, err
+}
+
+func decodeWithdrawSrm(buf *bytes.Reader) (WithdrawSrm, error) {
+	var instruction WithdrawSrm
+	if err := expectLength("WithdrawSrm", buf, 8); err != nil {
+		return instruction, err
+	}
+
+	err := read(buf, &instruction.Amount)
+	return instruction, err
+}
+
+func decodePreInitialize(buf *bytes.Reader) (PreInitialize, error) {
+	var instruction PreInitialize
+	if err := expectLength("PreInitialize", buf, 1); err != nil {
+		return instruction, err
+	}
+
+	err := read(buf, &instruction.Nonce)
+	return instruction, err
+}
+
 func decodeSwapBaseIn(buf *bytes.Reader) (SwapBaseIn, error) {
 	var instruction SwapBaseIn
-	binary.Read(buf, binary.LittleEndian, &instruction.AmountIn)
-	binary.Read(buf, binary.LittleEndian, &instruction.MinimumAmountOut)
+	if err := expectLength("SwapBaseIn", buf, 16); err != nil {
+		return instruction, err
+	}
 
-	return instruction, nil
+	err := read(buf, &instruction.AmountIn, &instruction.MinimumAmountOut)
+	return instruction, err
 }
 
 func decodeSwapBaseOut(buf *bytes.Reader) (SwapBaseOut, error) {
 	var instruction SwapBaseOut
-	binary.Read(buf, binary.LittleEndian, &instruction.MaxAmountIn)
-	binary.Read(buf, binary.LittleEndian, &instruction.AmountOut)
+	if err := expectLength("SwapBaseOut", buf, 16); err != nil {
+		return instruction, err
+	}
+
+	err := read(buf, &instruction.MaxAmountIn, &instruction.AmountOut)
+	return instruction, err
+}
+
+func decodeSimulateInfo(buf *bytes.Reader) (SimulateInfo, error) {
+	var instruction SimulateInfo
+	if err := read(buf, &instruction.Param); err != nil {
+		return instruction, err
+	}
+
+	switch instruction.Param {
+	case 1:
+		swap, err := decodeSwapBaseIn(buf)
+		if err != nil {
+			return instruction, err
+		}
+		instruction.SwapBaseIn = &swap
+	case 2:
+		swap, err := decodeSwapBaseOut(buf)
+		if err != nil {
+			return instruction, err
+		}
+		instruction.SwapBaseOut = &swap
+	default:
+		return instruction, expectLength("SimulateInfo", buf, 0)
+	}
 
 	return instruction, nil
 }
+
+func decodeAdminCancelOrders(buf *bytes.Reader) (AdminCancelOrders, error) {
+	var instruction AdminCancelOrders
+	if err := expectLength("AdminCancelOrders", buf, 2); err != nil {
+		return instruction, err
+	}
+
+	err := read(buf, &instruction.Limit)
+	return instruction, err
+}
+
+func decodeUpdateConfigAccount(buf *bytes.Reader) (UpdateConfigAccount, error) {
+	var instruction UpdateConfigAccount
+	if err := read(buf, &instruction.Param); err != nil {
+		return instruction, err
+	}
+
+	var err error
+	switch instruction.Param {
+	case 0, 1:
+		if err = expectLength("UpdateConfigAccount", buf, 32); err == nil {
+			instruction.Owner = new(solana.PublicKey)
+			err = read(buf, instruction.Owner)
+		}
+	case 2:
+		if err = expectLength("UpdateConfigAccount", buf, 8); err == nil {
+			instruction.CreatePoolFee = new(uint64)
+			err = read(buf, instruction.CreatePoolFee)
+		}
+	default:
+		err = unknownDiscriminator(instruction.Param)
+	}
+
+	return instruction, err
+}
@@ -0,0 +1,173 @@
+package coder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// The instruction and account data in these tests is built by hand from the
+// program layouts, not captured from mainnet transactions. Anchor discriminators
+// are written out as bytes rather than derived with AnchorDiscriminator, so a
+// misspelt instruction name in a coder fails its test.
+
+// le concatenates the little endian encoding of each value.
+func le(values ...interface{}) []byte {
+	var buf bytes.Buffer
+	for _, value := range values {
+		if err := binary.Write(&buf, binary.LittleEndian, value); err != nil {
+			panic(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+// disc decodes a hex discriminator.
+func disc(s string) []byte {
+	discriminator, err := hex.DecodeString(s)
+	if err != nil || len(discriminator) != 8 {
+		panic("invalid discriminator " + s)
+	}
+	return discriminator
+}
+
+// account returns size zeroed bytes with each value written at its offset.
+func account(size int, fields map[int]interface{}) []byte {
+	data := make([]byte, size)
+	for offset, value := range fields {
+		copy(data[offset:], le(value))
+	}
+	return data
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func testKey(seed byte) solana.PublicKey {
+	var key solana.PublicKey
+	for i := range key {
+		key[i] = seed + byte(i)
+	}
+	return key
+}
+
+type decodeTest struct {
+	name    string
+	data    []byte
+	want    interface{}
+	wantErr error
+}
+
+func runDecodeTests(t *testing.T, decoder InstructionDecoder, tests []decodeTest) {
+	t.Helper()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decoder.Decode(tt.data)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Decode() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRaydiumAmmDecode(t *testing.T) {
+	owner := testKey(1)
+
+	runDecodeTests(t, NewRaydiumAmmInstructionCoder(), []decodeTest{
+		{
+			name: "Initialize2",
+			data: le(uint8(1), uint8(254), uint64(1_700_000_000), uint64(5_000_000_000), uint64(1_000_000_000_000)),
+			want: Initialize2{Nonce: 254, OpenTime: 1_700_000_000, InitPcAmount: 5_000_000_000, InitCoinAmount: 1_000_000_000_000},
+		},
+		{
+			name: "Deposit",
+			data: le(uint8(3), uint64(100), uint64(200), uint64(1), uint64(150)),
+			want: Deposit{MaxCoinAmount: 100, MaxPcAmount: 200, BaseSide: 1, OtherAmountMin: ptr[uint64](150)},
+		},
+		{
+			name: "Deposit without minimum",
+			data: le(uint8(3), uint64(100), uint64(200), uint64(0)),
+			want: Deposit{MaxCoinAmount: 100, MaxPcAmount: 200},
+		},
+		{
+			name: "Withdraw",
+			data: le(uint8(4), uint64(42), uint64(10), uint64(20)),
+			want: Withdraw{Amount: 42, MinCoinAmount: ptr[uint64](10), MinPcAmount: ptr[uint64](20)},
+		},
+		{
+			name: "Withdraw without minimums",
+			data: le(uint8(4), uint64(42)),
+			want: Withdraw{Amount: 42},
+		},
+		{
+			name: "SetParams owner",
+			data: le(uint8(6), uint8(PARAM_AMM_OWNER), owner),
+			want: SetParams{Param: PARAM_AMM_OWNER, NewPubkey: &owner},
+		},
+		{
+			name: "SetParams value",
+			data: le(uint8(6), uint8(PARAM_STATUS), uint64(6)),
+			want: SetParams{Param: PARAM_STATUS, Value: ptr[uint64](6)},
+		},
+		{
+			name: "SwapBaseIn",
+			data: le(uint8(9), uint64(1_000_000), uint64(990)),
+			want: SwapBaseIn{AmountIn: 1_000_000, MinimumAmountOut: 990},
+		},
+		{
+			name: "SwapBaseOut",
+			data: le(uint8(11), uint64(1_010), uint64(1_000)),
+			want: SwapBaseOut{MaxAmountIn: 1_010, AmountOut: 1_000},
+		},
+		{
+			name: "SimulateInfo swap base in",
+			data: le(uint8(12), uint8(1), uint64(5), uint64(4)),
+			want: SimulateInfo{Param: 1, SwapBaseIn: &SwapBaseIn{AmountIn: 5, MinimumAmountOut: 4}},
+		},
+		{
+			name: "UpdateConfigAccount fee",
+			data: le(uint8(15), uint8(2), uint64(150_000_000)),
+			want: UpdateConfigAccount{Param: 2, CreatePoolFee: ptr[uint64](150_000_000)},
+		},
+		{
+			name: "CreateConfigAccount",
+			data: le(uint8(14)),
+			want: CreateConfigAccount{},
+		},
+		{name: "empty", data: nil, wantErr: ErrShortData},
+		{name: "short SwapBaseIn", data: le(uint8(9), uint64(1)), wantErr: ErrShortData},
+		{name: "short Withdraw minimums", data: le(uint8(4), uint64(42), uint64(10)), wantErr: ErrShortData},
+		{name: "long SwapBaseIn", data: le(uint8(9), uint64(1), uint64(2), uint8(0)), wantErr: ErrTrailingData},
+		{name: "short SetParams owner", data: le(uint8(6), uint8(PARAM_AMM_OWNER), uint64(0)), wantErr: ErrShortData},
+		{name: "unknown instruction", data: le(uint8(16)), wantErr: ErrUnknownDiscriminator},
+		{name: "unknown UpdateConfigAccount param", data: le(uint8(15), uint8(3)), wantErr: ErrUnknownDiscriminator},
+	})
+}
+
+func TestDecodeFailures(t *testing.T) {
+	before := DecodeFailures()[PROGRAM_RAYDIUM_AMM_V4]["unknown_discriminator"]
+
+	NewRaydiumAmmInstructionCoder().Decode([]byte{255})
+
+	if got := DecodeFailures()[PROGRAM_RAYDIUM_AMM_V4]["unknown_discriminator"]; got != before+1 {
+		t.Errorf("unknown_discriminator failures = %d, want %d", got, before+1)
+	}
+}
@@ -3,6 +3,7 @@ package coder
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 
 	"github.com/gagliardetto/solana-go"
 )
@@ -74,10 +75,13 @@ func (coder *RaydiumLiquidityCoder) RaydiumLiquidityDecode(data []byte) (Liquidi
 }
 
 func decodeRaydiumLiqudityData(data []byte) (LiquidityState, error) {
-	buf := bytes.NewReader(data)
 	var state LiquidityState
 
-	binary.Read(buf, binary.LittleEndian, &state)
+	// Account data can carry padding past the layout, so only a short account is an error
+	if size := binary.Size(state); len(data) < size {
+		return state, fmt.Errorf("%w: LiquidityState needs %d bytes, got %d", ErrShortData, size, len(data))
+	}
 
-	return state, nil
+	err := read(bytes.NewReader(data), &state)
+	return state, err
 }
@@ -3,6 +3,7 @@ package coder
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 
 	"github.com/gagliardetto/solana-go"
 )
@@ -44,10 +45,13 @@ func (coder *RaydiumMarketCoder) RaydiumMarketDecode(data []byte) (MarketStateLa
 }
 
 func decodeRaydiumMarketData(data []byte) (MarketStateLayoutV3, error) {
-	buf := bytes.NewReader(data)
 	var state MarketStateLayoutV3
 
-	binary.Read(buf, binary.LittleEndian, &state)
+	// Account data can carry padding past the layout, so only a short account is an error
+	if size := binary.Size(state); len(data) < size {
+		return state, fmt.Errorf("%w: MarketStateLayoutV3 needs %d bytes, got %d", ErrShortData, size, len(data))
+	}
 
-	return state, nil
+	err := read(bytes.NewReader(data), &state)
+	return state, err
 }
@@ -0,0 +1,210 @@
+package coder
+
+import (
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	dlmmRemoveLiquidity        = AnchorDiscriminator("remove_liquidity")
+	dlmmRemoveAllLiquidity     = AnchorDiscriminator("remove_all_liquidity")
+	dlmmRemoveLiquidityByRange = AnchorDiscriminator("remove_liquidity_by_range")
+	dlmmClosePosition          = AnchorDiscriminator("close_position")
+	dlmmLbPair                 = AnchorAccountDiscriminator("LbPair")
+	dammRemoveBalanceLiquidity = AnchorDiscriminator("remove_balance_liquidity")
+	dammPool                   = AnchorAccountDiscriminator("Pool")
+	dammVault                  = AnchorAccountDiscriminator("Vault")
+)
+
+// Account positions in the Meteora instructions
+const (
+	DLMM_REMOVE_POSITION        = 0
+	DLMM_REMOVE_LB_PAIR         = 1
+	DLMM_REMOVE_TOKEN_X_MINT    = 7
+	DLMM_REMOVE_TOKEN_Y_MINT    = 8
+	DLMM_REMOVE_SENDER          = 11
+	DLMM_CLOSE_POSITION         = 0
+	DLMM_CLOSE_LB_PAIR          = 1
+	DLMM_CLOSE_SENDER           = 4
+	DAMM_REMOVE_POOL            = 0
+	DAMM_REMOVE_A_VAULT_LP      = 3
+	DAMM_REMOVE_B_VAULT_LP      = 4
+	DAMM_REMOVE_A_VAULT_LP_MINT = 7
+	DAMM_REMOVE_B_VAULT_LP_MINT = 8
+	DAMM_REMOVE_USER            = 13
+)
+
+type DlmmBinLiquidityReduction struct {
+	BinId       int32
+	BpsToRemove uint16
+}
+
+type DlmmRemoveLiquidity struct {
+	Bins []DlmmBinLiquidityReduction
+}
+
+type DlmmRemoveAllLiquidity struct{}
+
+type DlmmRemoveLiquidityByRange struct {
+	FromBinId   int32
+	ToBinId     int32
+	BpsToRemove uint16
+}
+
+type DlmmClosePosition struct{}
+
+// DlmmLbPair is the leading part of a DLMM pair account, up to the reserves.
+type DlmmLbPair struct {
+	Parameters              [32]byte
+	VParameters             [32]byte
+	BumpSeed                [1]byte
+	BinStepSeed             [2]byte
+	PairType                uint8
+	ActiveId                int32
+	BinStep                 uint16
+	Status                  uint8
+	RequireBaseFactorSeed   uint8
+	BaseFactorSeed          [2]byte
+	ActivationType          uint8
+	CreatorPoolOnOffControl uint8
+	TokenXMint              solana.PublicKey
+	TokenYMint              solana.PublicKey
+	ReserveX                solana.PublicKey
+	ReserveY                solana.PublicKey
+}
+
+type DammRemoveBalanceLiquidity struct {
+	PoolTokenAmount  uint64
+	MinimumATokenOut uint64
+	MinimumBTokenOut uint64
+}
+
+// DammPool is the leading part of a dynamic AMM pool account. The tokens sit in
+// Meteora vaults shared between pools, and the pool holds LP of each vault.
+type DammPool struct {
+	LpMint     solana.PublicKey
+	TokenAMint solana.PublicKey
+	TokenBMint solana.PublicKey
+	AVault     solana.PublicKey
+	BVault     solana.PublicKey
+	AVaultLp   solana.PublicKey
+	BVaultLp   solana.PublicKey
+}
+
+// DammVault is the leading part of a Meteora vault account. TokenVault holds the
+// deposits of every pool using the vault, apart from what is lent to strategies.
+type DammVault struct {
+	Enabled     uint8
+	Bumps       [2]byte
+	TotalAmount uint64
+	TokenVault  solana.PublicKey
+	FeeVault    solana.PublicKey
+	TokenMint   solana.PublicKey
+	LpMint      solana.PublicKey
+}
+
+// MeteoraDlmmCoder decodes Meteora DLMM liquidity removal instructions and pair state.
+type MeteoraDlmmCoder struct{}
+
+func NewMeteoraDlmmCoder() *MeteoraDlmmCoder {
+	return &MeteoraDlmmCoder{}
+}
+
+// Decode decodes the given byte array into an instruction.
+func (coder *MeteoraDlmmCoder) Decode(data []byte) (interface{}, error) {
+	decoded, err := decodeDlmm(data)
+	if err != nil {
+		recordDecodeFailure(PROGRAM_METEORA_DLMM, err)
+	}
+	return decoded, err
+}
+
+func (coder *MeteoraDlmmCoder) DecodeLbPair(data []byte) (DlmmLbPair, error) {
+	var state DlmmLbPair
+	err := decodeAnchorAccount("DlmmLbPair", dlmmLbPair, data, &state)
+	return state, err
+}
+
+func decodeDlmm(data []byte) (interface{}, error) {
+	buf, discriminator, err := readDiscriminator(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch discriminator {
+	case dlmmRemoveLiquidity:
+		var count uint32
+		if err := read(buf, &count); err != nil {
+			return nil, err
+		}
+
+		if err := expectLength("DlmmRemoveLiquidity", buf, int(count)*6); err != nil {
+			return nil, err
+		}
+
+		instruction := DlmmRemoveLiquidity{Bins: make([]DlmmBinLiquidityReduction, count)}
+		for i := range instruction.Bins {
+			if err := read(buf, &instruction.Bins[i].BinId, &instruction.Bins[i].BpsToRemove); err != nil {
+				return nil, err
+			}
+		}
+		return instruction, nil
+	case dlmmRemoveAllLiquidity:
+		return DlmmRemoveAllLiquidity{}, expectLength("DlmmRemoveAllLiquidity", buf, 0)
+	case dlmmRemoveLiquidityByRange:
+		var instruction DlmmRemoveLiquidityByRange
+		if err := expectLength("DlmmRemoveLiquidityByRange", buf, 10); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction.FromBinId, &instruction.ToBinId, &instruction.BpsToRemove)
+	case dlmmClosePosition:
+		return DlmmClosePosition{}, expectLength("DlmmClosePosition", buf, 0)
+	default:
+		return nil, unknownDiscriminator(discriminator)
+	}
+}
+
+// MeteoraDammCoder decodes Meteora dynamic AMM liquidity removal and pool state.
+type MeteoraDammCoder struct{}
+
+func NewMeteoraDammCoder() *MeteoraDammCoder {
+	return &MeteoraDammCoder{}
+}
+
+// Decode decodes the given byte array into an instruction.
+func (coder *MeteoraDammCoder) Decode(data []byte) (interface{}, error) {
+	decoded, err := decodeDamm(data)
+	if err != nil {
+		recordDecodeFailure(PROGRAM_METEORA_DAMM, err)
+	}
+	return decoded, err
+}
+
+func (coder *MeteoraDammCoder) DecodePool(data []byte) (DammPool, error) {
+	var state DammPool
+	err := decodeAnchorAccount("DammPool", dammPool, data, &state)
+	return state, err
+}
+
+func (coder *MeteoraDammCoder) DecodeVault(data []byte) (DammVault, error) {
+	var state DammVault
+	err := decodeAnchorAccount("DammVault", dammVault, data, &state)
+	return state, err
+}
+
+func decodeDamm(data []byte) (interface{}, error) {
+	buf, discriminator, err := readDiscriminator(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch discriminator {
+	case dammRemoveBalanceLiquidity:
+		var instruction DammRemoveBalanceLiquidity
+		if err := expectLength("DammRemoveBalanceLiquidity", buf, 24); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction.PoolTokenAmount, &instruction.MinimumATokenOut, &instruction.MinimumBTokenOut)
+	default:
+		return nil, unknownDiscriminator(discriminator)
+	}
+}
@@ -0,0 +1,132 @@
+package coder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMeteoraDlmmDecode(t *testing.T) {
+	runDecodeTests(t, NewMeteoraDlmmCoder(), []decodeTest{
+		{
+			name: "remove_liquidity",
+			data: append(disc("5055d14818ceb16c"), le(uint32(2), int32(-5), uint16(10_000), int32(3), uint16(5_000))...),
+			want: DlmmRemoveLiquidity{Bins: []DlmmBinLiquidityReduction{{BinId: -5, BpsToRemove: 10_000}, {BinId: 3, BpsToRemove: 5_000}}},
+		},
+		{
+			name: "remove_liquidity without bins",
+			data: append(disc("5055d14818ceb16c"), le(uint32(0))...),
+			want: DlmmRemoveLiquidity{Bins: []DlmmBinLiquidityReduction{}},
+		},
+		{
+			name: "remove_all_liquidity",
+			data: disc("0a333d2370691855"),
+			want: DlmmRemoveAllLiquidity{},
+		},
+		{
+			name: "remove_liquidity_by_range",
+			data: append(disc("1a526698f04a691a"), le(int32(-10), int32(10), uint16(10_000))...),
+			want: DlmmRemoveLiquidityByRange{FromBinId: -10, ToBinId: 10, BpsToRemove: 10_000},
+		},
+		{
+			name: "close_position",
+			data: disc("7b86510031446262"),
+			want: DlmmClosePosition{},
+		},
+		{name: "remove_liquidity with fewer bins than counted", data: append(disc("5055d14818ceb16c"), le(uint32(2), int32(-5), uint16(10_000))...), wantErr: ErrShortData},
+		{name: "remove_liquidity without a count", data: disc("5055d14818ceb16c"), wantErr: ErrShortData},
+		{name: "short remove_liquidity_by_range", data: append(disc("1a526698f04a691a"), le(int32(-10), int32(10))...), wantErr: ErrShortData},
+		{name: "remove_all_liquidity with data", data: append(disc("0a333d2370691855"), 0), wantErr: ErrTrailingData},
+		{name: "unknown discriminator", data: disc("856d2cb338ee7221"), wantErr: ErrUnknownDiscriminator},
+	})
+}
+
+func TestMeteoraDlmmDecodeLbPair(t *testing.T) {
+	var (
+		mintX    = testKey(10)
+		mintY    = testKey(20)
+		reserveX = testKey(30)
+		reserveY = testKey(40)
+	)
+
+	// Offsets of the on-chain LbPair
+	data := account(904, map[int]interface{}{
+		0:   disc("210b3162b565b10d"),
+		76:  int32(-1_234),
+		80:  uint16(25),
+		88:  mintX,
+		120: mintY,
+		152: reserveX,
+		184: reserveY,
+	})
+
+	pair, err := NewMeteoraDlmmCoder().DecodeLbPair(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pair.ActiveId != -1_234 || pair.BinStep != 25 {
+		t.Errorf("active id = %d, bin step = %d", pair.ActiveId, pair.BinStep)
+	}
+	if pair.TokenXMint != mintX || pair.TokenYMint != mintY || pair.ReserveX != reserveX || pair.ReserveY != reserveY {
+		t.Errorf("keys = %s %s %s %s", pair.TokenXMint, pair.TokenYMint, pair.ReserveX, pair.ReserveY)
+	}
+
+	if _, err := NewMeteoraDlmmCoder().DecodeLbPair(data[:200]); !errors.Is(err, ErrShortData) {
+		t.Errorf("truncated account: err = %v, want %v", err, ErrShortData)
+	}
+}
+
+func TestMeteoraDammDecode(t *testing.T) {
+	runDecodeTests(t, NewMeteoraDammCoder(), []decodeTest{
+		{
+			name: "remove_balance_liquidity",
+			data: append(disc("856d2cb338ee7221"), le(uint64(1_000), uint64(10), uint64(20))...),
+			want: DammRemoveBalanceLiquidity{PoolTokenAmount: 1_000, MinimumATokenOut: 10, MinimumBTokenOut: 20},
+		},
+		{name: "short remove_balance_liquidity", data: append(disc("856d2cb338ee7221"), le(uint64(1_000))...), wantErr: ErrShortData},
+		{name: "long remove_balance_liquidity", data: append(disc("856d2cb338ee7221"), le(uint64(1), uint64(2), uint64(3), uint8(0))...), wantErr: ErrTrailingData},
+		{name: "unknown discriminator", data: disc("5055d14818ceb16c"), wantErr: ErrUnknownDiscriminator},
+	})
+}
+
+func TestMeteoraDammDecodeAccounts(t *testing.T) {
+	var (
+		lpMint   = testKey(10)
+		aVault   = testKey(20)
+		bVaultLp = testKey(30)
+		token    = testKey(40)
+	)
+
+	pool, err := NewMeteoraDammCoder().DecodePool(account(944, map[int]interface{}{
+		0:   disc("f19a6d0411b16dbc"),
+		8:   lpMint,
+		104: aVault,
+		200: bVaultLp,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pool.LpMint != lpMint || pool.AVault != aVault || pool.BVaultLp != bVaultLp {
+		t.Errorf("pool = %+v", pool)
+	}
+
+	vault, err := NewMeteoraDammCoder().DecodeVault(account(1232, map[int]interface{}{
+		0:   disc("d308e82b02987577"),
+		8:   uint8(1),
+		11:  uint64(5_000_000),
+		19:  token,
+		83:  testKey(50),
+		115: lpMint,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vault.Enabled != 1 || vault.TotalAmount != 5_000_000 || vault.TokenVault != token || vault.LpMint != lpMint {
+		t.Errorf("vault = %+v", vault)
+	}
+
+	// A vault account is not a pool
+	if _, err := NewMeteoraDammCoder().DecodePool(account(1232, map[int]interface{}{0: disc("d308e82b02987577")})); !errors.Is(err, ErrUnknownDiscriminator) {
+		t.Errorf("vault as pool: err = %v, want %v", err, ErrUnknownDiscriminator)
+	}
+}
@@ -0,0 +1,115 @@
+package coder
+
+import (
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	pumpCreate   = AnchorDiscriminator("create")
+	pumpBuy      = AnchorDiscriminator("buy")
+	pumpSell     = AnchorDiscriminator("sell")
+	pumpWithdraw = AnchorDiscriminator("withdraw")
+	pumpMigrate  = AnchorDiscriminator("migrate")
+)
+
+// Account positions in the pump.fun instructions
+const (
+	PUMP_CREATE_MINT           = 0
+	PUMP_CREATE_BONDING_CURVE  = 2
+	PUMP_CREATE_USER           = 7
+	PUMP_TRADE_MINT            = 2
+	PUMP_TRADE_BONDING_CURVE   = 3
+	PUMP_TRADE_USER            = 6
+	PUMP_MIGRATE_MINT          = 2
+	PUMP_MIGRATE_BONDING_CURVE = 3
+)
+
+// PumpCreate launches a token on a bonding curve. Creator is only passed by
+// newer clients, older ones leave the signer as the creator.
+type PumpCreate struct {
+	Name    string
+	Symbol  string
+	Uri     string
+	Creator *solana.PublicKey
+}
+
+type PumpBuy struct {
+	Amount     uint64
+	MaxSolCost uint64
+}
+
+type PumpSell struct {
+	Amount       uint64
+	MinSolOutput uint64
+}
+
+// PumpWithdraw takes the reserves of a completed bonding curve out for the
+// migration into a Raydium pool.
+type PumpWithdraw struct{}
+
+// PumpMigrate moves a completed bonding curve into a pool in a single instruction.
+type PumpMigrate struct{}
+
+// PumpCoder decodes pump.fun bonding curve instructions.
+type PumpCoder struct{}
+
+func NewPumpCoder() *PumpCoder {
+	return &PumpCoder{}
+}
+
+// Decode decodes the given byte array into an instruction.
+func (coder *PumpCoder) Decode(data []byte) (interface{}, error) {
+	decoded, err := decodePump(data)
+	if err != nil {
+		recordDecodeFailure(PROGRAM_PUMP_FUN, err)
+	}
+	return decoded, err
+}
+
+func decodePump(data []byte) (interface{}, error) {
+	buf, discriminator, err := readDiscriminator(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch discriminator {
+	case pumpCreate:
+		var instruction PumpCreate
+		for _, field := range []*string{&instruction.Name, &instruction.Symbol, &instruction.Uri} {
+			if *field, err = readBorshString(buf); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := expectLength("PumpCreate", buf, 0, 32); err != nil {
+			return nil, err
+		}
+
+		if buf.Len() > 0 {
+			instruction.Creator = new(solana.PublicKey)
+			if err := read(buf, instruction.Creator); err != nil {
+				return nil, err
+			}
+		}
+		return instruction, nil
+	case pumpBuy:
+		var instruction PumpBuy
+		// Newer clients append a track_volume flag
+		if err := expectLength("PumpBuy", buf, 16, 17); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction.Amount, &instruction.MaxSolCost)
+	case pumpSell:
+		var instruction PumpSell
+		if err := expectLength("PumpSell", buf, 16); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction.Amount, &instruction.MinSolOutput)
+	case pumpWithdraw:
+		return PumpWithdraw{}, expectLength("PumpWithdraw", buf, 0)
+	case pumpMigrate:
+		return PumpMigrate{}, expectLength("PumpMigrate", buf, 0)
+	default:
+		return nil, unknownDiscriminator(discriminator)
+	}
+}
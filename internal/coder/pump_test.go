@@ -0,0 +1,61 @@
+package coder
+
+import "testing"
+
+// borshString encodes a string as a u32 length followed by its bytes.
+func borshString(s string) []byte {
+	return append(le(uint32(len(s))), s...)
+}
+
+func TestPumpDecode(t *testing.T) {
+	creator := testKey(1)
+
+	create := disc("181ec828051c0777")
+	for _, field := range []string{"Token", "TKN", "https://example.com/token.json"} {
+		create = append(create, borshString(field)...)
+	}
+
+	runDecodeTests(t, NewPumpCoder(), []decodeTest{
+		{
+			name: "create",
+			data: create,
+			want: PumpCreate{Name: "Token", Symbol: "TKN", Uri: "https://example.com/token.json"},
+		},
+		{
+			name: "create with creator",
+			data: append(append([]byte{}, create...), creator[:]...),
+			want: PumpCreate{Name: "Token", Symbol: "TKN", Uri: "https://example.com/token.json", Creator: &creator},
+		},
+		{
+			name: "buy",
+			data: append(disc("66063d1201daebea"), le(uint64(1_000_000), uint64(50_000_000))...),
+			want: PumpBuy{Amount: 1_000_000, MaxSolCost: 50_000_000},
+		},
+		{
+			name: "buy with track_volume",
+			data: append(disc("66063d1201daebea"), le(uint64(1_000_000), uint64(50_000_000), true)...),
+			want: PumpBuy{Amount: 1_000_000, MaxSolCost: 50_000_000},
+		},
+		{
+			name: "sell",
+			data: append(disc("33e685a4017f83ad"), le(uint64(1_000_000), uint64(40_000_000))...),
+			want: PumpSell{Amount: 1_000_000, MinSolOutput: 40_000_000},
+		},
+		{
+			name: "withdraw",
+			data: disc("b712469c946da122"),
+			want: PumpWithdraw{},
+		},
+		{
+			name: "migrate",
+			data: disc("9beae792ec9ea21e"),
+			want: PumpMigrate{},
+		},
+		{name: "short discriminator", data: []byte{0x66, 0x06}, wantErr: ErrShortData},
+		{name: "create string past the end", data: append(disc("181ec828051c0777"), le(uint32(10), []byte("abc"))...), wantErr: ErrShortData},
+		{name: "create with part of a creator", data: append(append([]byte{}, create...), creator[:16]...), wantErr: ErrShortData},
+		{name: "short buy", data: append(disc("66063d1201daebea"), le(uint64(1_000_000))...), wantErr: ErrShortData},
+		{name: "long sell", data: append(disc("33e685a4017f83ad"), le(uint64(1), uint64(2), uint8(0))...), wantErr: ErrTrailingData},
+		{name: "unknown discriminator", data: disc("f8c69e91e17587c8"), wantErr: ErrUnknownDiscriminator},
+	})
+}
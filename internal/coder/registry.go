@@ -0,0 +1,46 @@
+package coder
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// InstructionDecoder decodes the instruction data of one program into one of the
+// instruction types of this package.
+type InstructionDecoder interface {
+	Decode(data []byte) (interface{}, error)
+}
+
+// Registry maps program IDs to the decoder of their instructions. Decoders are
+// registered at startup, before any transaction is decoded.
+type Registry struct {
+	decoders map[solana.PublicKey]InstructionDecoder
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		decoders: make(map[solana.PublicKey]InstructionDecoder),
+	}
+}
+
+// Register sets the decoder of programId, replacing any decoder registered before.
+func (registry *Registry) Register(programId solana.PublicKey, decoder InstructionDecoder) {
+	registry.decoders[programId] = decoder
+}
+
+// Has reports whether a decoder is registered for programId.
+func (registry *Registry) Has(programId solana.PublicKey) bool {
+	_, ok := registry.decoders[programId]
+	return ok
+}
+
+// Decode decodes data with the decoder of programId.
+func (registry *Registry) Decode(programId solana.PublicKey, data []byte) (interface{}, error) {
+	decoder, ok := registry.decoders[programId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProgram, programId)
+	}
+
+	return decoder.Decode(data)
+}
@@ -0,0 +1,212 @@
+package coder
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// System program instructions are bincode encoded: a u32 tag followed by the
+// arguments, with seeds as a u64 length prefixed string.
+type SystemCreateAccount struct {
+	Lamports uint64
+	Space    uint64
+	Owner    solana.PublicKey
+}
+
+type SystemAssign struct {
+	Owner solana.PublicKey
+}
+
+type SystemTransfer struct {
+	Lamports uint64
+}
+
+type SystemCreateAccountWithSeed struct {
+	Base     solana.PublicKey
+	Seed     string
+	Lamports uint64
+	Space    uint64
+	Owner    solana.PublicKey
+}
+
+type SystemAdvanceNonceAccount struct{}
+
+type SystemWithdrawNonceAccount struct {
+	Lamports uint64
+}
+
+type SystemInitializeNonceAccount struct {
+	Authority solana.PublicKey
+}
+
+type SystemAuthorizeNonceAccount struct {
+	Authority solana.PublicKey
+}
+
+type SystemAllocate struct {
+	Space uint64
+}
+
+type SystemAllocateWithSeed struct {
+	Base  solana.PublicKey
+	Seed  string
+	Space uint64
+	Owner solana.PublicKey
+}
+
+type SystemAssignWithSeed struct {
+	Base  solana.PublicKey
+	Seed  string
+	Owner solana.PublicKey
+}
+
+type SystemTransferWithSeed struct {
+	Lamports  uint64
+	FromSeed  string
+	FromOwner solana.PublicKey
+}
+
+type SystemUpgradeNonceAccount struct{}
+
+// SystemCoder decodes System program instructions.
+type SystemCoder struct{}
+
+func NewSystemCoder() *SystemCoder {
+	return &SystemCoder{}
+}
+
+// Decode decodes the given byte array into an instruction.
+func (coder *SystemCoder) Decode(data []byte) (interface{}, error) {
+	decoded, err := decodeSystem(data)
+	if err != nil {
+		recordDecodeFailure(PROGRAM_SYSTEM, err)
+	}
+	return decoded, err
+}
+
+// LamportsTransferred returns the lamports a decoded instruction moves out of the
+// funding account and the position of the receiving account in the instruction.
+func LamportsTransferred(decoded interface{}) (lamports uint64, destination int, ok bool) {
+	switch instruction := decoded.(type) {
+	case SystemTransfer:
+		return instruction.Lamports, 1, true
+	case SystemTransferWithSeed:
+		return instruction.Lamports, 2, true
+	case SystemWithdrawNonceAccount:
+		return instruction.Lamports, 1, true
+	}
+	return 0, 0, false
+}
+
+func decodeSystem(data []byte) (interface{}, error) {
+	buf := bytes.NewReader(data)
+	var instructionID uint32
+	if err := read(buf, &instructionID); err != nil {
+		return nil, err
+	}
+
+	var (
+		instruction interface{}
+		err         error
+	)
+
+	switch instructionID {
+	case 0:
+		var ix SystemCreateAccount
+		err = read(buf, &ix.Lamports, &ix.Space, &ix.Owner)
+		instruction = ix
+	case 1:
+		var ix SystemAssign
+		err = read(buf, &ix.Owner)
+		instruction = ix
+	case 2:
+		var ix SystemTransfer
+		err = read(buf, &ix.Lamports)
+		instruction = ix
+	case 3:
+		var ix SystemCreateAccountWithSeed
+		if err = read(buf, &ix.Base); err == nil {
+			if ix.Seed, err = readSeed(buf); err == nil {
+				err = read(buf, &ix.Lamports, &ix.Space, &ix.Owner)
+			}
+		}
+		instruction = ix
+	case 4:
+		instruction = SystemAdvanceNonceAccount{}
+	case 5:
+		var ix SystemWithdrawNonceAccount
+		err = read(buf, &ix.Lamports)
+		instruction = ix
+	case 6:
+		var ix SystemInitializeNonceAccount
+		err = read(buf, &ix.Authority)
+		instruction = ix
+	case 7:
+		var ix SystemAuthorizeNonceAccount
+		err = read(buf, &ix.Authority)
+		instruction = ix
+	case 8:
+		var ix SystemAllocate
+		err = read(buf, &ix.Space)
+		instruction = ix
+	case 9:
+		var ix SystemAllocateWithSeed
+		if err = read(buf, &ix.Base); err == nil {
+			if ix.Seed, err = readSeed(buf); err == nil {
+				err = read(buf, &ix.Space, &ix.Owner)
+			}
+		}
+		instruction = ix
+	case 10:
+		var ix SystemAssignWithSeed
+		if err = read(buf, &ix.Base); err == nil {
+			if ix.Seed, err = readSeed(buf); err == nil {
+				err = read(buf, &ix.Owner)
+			}
+		}
+		instruction = ix
+	case 11:
+		var ix SystemTransferWithSeed
+		if err = read(buf, &ix.Lamports); err == nil {
+			if ix.FromSeed, err = readSeed(buf); err == nil {
+				err = read(buf, &ix.FromOwner)
+			}
+		}
+		instruction = ix
+	case 12:
+		instruction = SystemUpgradeNonceAccount{}
+	default:
+		return nil, unknownDiscriminator(instructionID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Every field has been read, so anything left over is not a valid instruction
+	if err := expectLength(fmt.Sprintf("System(%d)", instructionID), buf, 0); err != nil {
+		return nil, err
+	}
+
+	return instruction, nil
+}
+
+// readSeed reads a bincode string, a u64 length followed by the bytes.
+func readSeed(buf *bytes.Reader) (string, error) {
+	var length uint64
+	if err := read(buf, &length); err != nil {
+		return "", err
+	}
+
+	if length > uint64(buf.Len()) {
+		return "", fmt.Errorf("%w: seed expects %d bytes, got %d", ErrShortData, length, buf.Len())
+	}
+
+	seed := make([]byte, length)
+	if err := read(buf, seed); err != nil {
+		return "", err
+	}
+	return string(seed), nil
+}
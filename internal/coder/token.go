@@ -0,0 +1,628 @@
+package coder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Account positions in SPL Token instructions, shared by Token-2022
+const (
+	TOKEN_INITIALIZE_ACCOUNT_ACCOUNT   = 0
+	TOKEN_INITIALIZE_ACCOUNT_MINT      = 1
+	TOKEN_INITIALIZE_ACCOUNT_OWNER     = 2
+	TOKEN_TRANSFER_SOURCE              = 0
+	TOKEN_TRANSFER_DESTINATION         = 1
+	TOKEN_SET_AUTHORITY_ACCOUNT        = 0
+	TOKEN_MINT_TO_MINT                 = 0
+	TOKEN_MINT_TO_DESTINATION          = 1
+	TOKEN_BURN_ACCOUNT                 = 0
+	TOKEN_BURN_MINT                    = 1
+	TOKEN_BURN_AUTHORITY               = 2
+	TOKEN_CLOSE_ACCOUNT_ACCOUNT        = 0
+	TOKEN_CLOSE_ACCOUNT_DESTINATION    = 1
+	TOKEN_FREEZE_ACCOUNT_ACCOUNT       = 0
+	TOKEN_FREEZE_ACCOUNT_MINT          = 1
+	TOKEN_TRANSFER_CHECKED_SOURCE      = 0
+	TOKEN_TRANSFER_CHECKED_MINT        = 1
+	TOKEN_TRANSFER_CHECKED_DESTINATION = 2
+	TOKEN_SET_TRANSFER_FEE_MINT        = 0
+	TOKEN_UPDATE_TRANSFER_HOOK_MINT    = 0
+)
+
+// Authority types of SetAuthority. Types from 4 on only exist on Token-2022.
+const (
+	TOKEN_AUTHORITY_MINT_TOKENS = iota
+	TOKEN_AUTHORITY_FREEZE_ACCOUNT
+	TOKEN_AUTHORITY_ACCOUNT_OWNER
+	TOKEN_AUTHORITY_CLOSE_ACCOUNT
+	TOKEN_AUTHORITY_TRANSFER_FEE_CONFIG
+	TOKEN_AUTHORITY_WITHHELD_WITHDRAW
+	TOKEN_AUTHORITY_CLOSE_MINT
+	TOKEN_AUTHORITY_INTEREST_RATE
+	TOKEN_AUTHORITY_PERMANENT_DELEGATE
+	TOKEN_AUTHORITY_CONFIDENTIAL_TRANSFER_MINT
+	TOKEN_AUTHORITY_TRANSFER_HOOK_PROGRAM_ID
+	TOKEN_AUTHORITY_CONFIDENTIAL_TRANSFER_FEE_CONFIG
+	TOKEN_AUTHORITY_METADATA_POINTER
+	TOKEN_AUTHORITY_GROUP_POINTER
+	TOKEN_AUTHORITY_GROUP_MEMBER_POINTER
+	TOKEN_AUTHORITY_SCALED_UI_AMOUNT
+	TOKEN_AUTHORITY_PAUSE
+)
+
+// Token-2022 extension instructions, each followed by its own instruction tag
+const (
+	TOKEN_2022_TRANSFER_FEE_EXTENSION  = 26
+	TOKEN_2022_TRANSFER_HOOK_EXTENSION = 36
+)
+
+// Token-2022 extension types stored on the mint account
+const (
+	TOKEN_2022_TRANSFER_FEE_CONFIG = 1
+	TOKEN_2022_PERMANENT_DELEGATE  = 12
+	TOKEN_2022_TRANSFER_HOOK       = 14
+)
+
+// Layout of the mint account. Token-2022 pads the mint to the size of a token
+// account and follows it with the account type and the extensions.
+const (
+	tokenMintSize        = 82
+	tokenAccountSize     = 165
+	tokenAccountTypeMint = 1
+)
+
+// TokenInitializeMint is InitializeMint, or InitializeMint2 which takes no rent sysvar.
+type TokenInitializeMint struct {
+	Decimals        uint8
+	MintAuthority   solana.PublicKey
+	FreezeAuthority *solana.PublicKey
+	V2              bool
+}
+
+// TokenInitializeAccount is InitializeAccount, which takes the owner as an account,
+// or InitializeAccount2 and 3, which take it as data.
+type TokenInitializeAccount struct {
+	Owner   *solana.PublicKey
+	Version uint8
+}
+
+type TokenInitializeMultisig struct {
+	M  uint8
+	V2 bool
+}
+
+type TokenTransfer struct {
+	Amount uint64
+}
+
+type TokenApprove struct {
+	Amount uint64
+}
+
+type TokenRevoke struct{}
+
+// TokenSetAuthority sets or, when NewAuthority is nil, removes an authority.
+type TokenSetAuthority struct {
+	AuthorityType uint8
+	NewAuthority  *solana.PublicKey
+}
+
+type TokenMintTo struct {
+	Amount uint64
+}
+
+type TokenBurn struct {
+	Amount uint64
+}
+
+type TokenCloseAccount struct{}
+
+type TokenFreezeAccount struct{}
+
+type TokenThawAccount struct{}
+
+type TokenTransferChecked struct {
+	Amount   uint64
+	Decimals uint8
+}
+
+type TokenApproveChecked struct {
+	Amount   uint64
+	Decimals uint8
+}
+
+type TokenMintToChecked struct {
+	Amount   uint64
+	Decimals uint8
+}
+
+type TokenBurnChecked struct {
+	Amount   uint64
+	Decimals uint8
+}
+
+type TokenSyncNative struct{}
+
+type TokenGetAccountDataSize struct {
+	ExtensionTypes []uint16
+}
+
+type TokenInitializeImmutableOwner struct{}
+
+type TokenAmountToUiAmount struct {
+	Amount uint64
+}
+
+type TokenUiAmountToAmount struct {
+	UiAmount string
+}
+
+type TokenInitializeMintCloseAuthority struct {
+	CloseAuthority *solana.PublicKey
+}
+
+type TokenInitializeTransferFeeConfig struct {
+	TransferFeeConfigAuthority *solana.PublicKey
+	WithdrawWithheldAuthority  *solana.PublicKey
+	TransferFeeBasisPoints     uint16
+	MaximumFee                 uint64
+}
+
+type TokenTransferCheckedWithFee struct {
+	Amount   uint64
+	Decimals uint8
+	Fee      uint64
+}
+
+type TokenWithdrawWithheldTokensFromMint struct{}
+
+type TokenWithdrawWithheldTokensFromAccounts struct {
+	NumTokenAccounts uint8
+}
+
+type TokenHarvestWithheldTokensToMint struct{}
+
+type TokenSetTransferFee struct {
+	TransferFeeBasisPoints uint16
+	MaximumFee             uint64
+}
+
+type TokenInitializePermanentDelegate struct {
+	Delegate solana.PublicKey
+}
+
+// TokenInitializeTransferHook sets the program every transfer of the mint calls.
+type TokenInitializeTransferHook struct {
+	Authority *solana.PublicKey
+	ProgramId *solana.PublicKey
+}
+
+type TokenUpdateTransferHook struct {
+	ProgramId *solana.PublicKey
+}
+
+// TokenExtension is a Token-2022 extension instruction that is recognised but whose
+// data is not decoded.
+type TokenExtension struct {
+	Extension uint8
+	Data      []byte
+}
+
+// SplTokenCoder decodes SPL Token instructions, and the Token-2022 extension
+// instructions when created for Token-2022.
+type SplTokenCoder struct {
+	program   string
+	token2022 bool
+}
+
+func NewSplTokenCoder() *SplTokenCoder {
+	return &SplTokenCoder{program: PROGRAM_SPL_TOKEN}
+}
+
+func NewToken2022Coder() *SplTokenCoder {
+	return &SplTokenCoder{program: PROGRAM_TOKEN_2022, token2022: true}
+}
+
+// Decode decodes the given byte array into an instruction.
+func (coder *SplTokenCoder) Decode(data []byte) (interface{}, error) {
+	decoded, err := coder.decode(data)
+	if err != nil {
+		recordDecodeFailure(coder.program, err)
+	}
+	return decoded, err
+}
+
+func (coder *SplTokenCoder) decode(data []byte) (interface{}, error) {
+	buf := bytes.NewReader(data)
+	var instructionID byte
+	if err := read(buf, &instructionID); err != nil {
+		return nil, err
+	}
+
+	var (
+		instruction interface{}
+		err         error
+	)
+
+	switch instructionID {
+	case 0, 20:
+		ix := TokenInitializeMint{V2: instructionID == 20}
+		if err = read(buf, &ix.Decimals, &ix.MintAuthority); err == nil {
+			ix.FreezeAuthority, err = readPubkeyOption(buf)
+		}
+		instruction = ix
+	case 1:
+		instruction = TokenInitializeAccount{Version: 1}
+	case 2, 19:
+		ix := TokenInitializeMultisig{V2: instructionID == 19}
+		err = read(buf, &ix.M)
+		instruction = ix
+	case 3:
+		var ix TokenTransfer
+		err = read(buf, &ix.Amount)
+		instruction = ix
+	case 4:
+		var ix TokenApprove
+		err = read(buf, &ix.Amount)
+		instruction = ix
+	case 5:
+		instruction = TokenRevoke{}
+	case 6:
+		var ix TokenSetAuthority
+		if err = read(buf, &ix.AuthorityType); err == nil {
+			ix.NewAuthority, err = readPubkeyOption(buf)
+		}
+		instruction = ix
+	case 7:
+		var ix TokenMintTo
+		err = read(buf, &ix.Amount)
+		instruction = ix
+	case 8:
+		var ix TokenBurn
+		err = read(buf, &ix.Amount)
+		instruction = ix
+	case 9:
+		instruction = TokenCloseAccount{}
+	case 10:
+		instruction = TokenFreezeAccount{}
+	case 11:
+		instruction = TokenThawAccount{}
+	case 12:
+		var ix TokenTransferChecked
+		err = read(buf, &ix.Amount, &ix.Decimals)
+		instruction = ix
+	case 13:
+		var ix TokenApproveChecked
+		err = read(buf, &ix.Amount, &ix.Decimals)
+		instruction = ix
+	case 14:
+		var ix TokenMintToChecked
+		err = read(buf, &ix.Amount, &ix.Decimals)
+		instruction = ix
+	case 15:
+		var ix TokenBurnChecked
+		err = read(buf, &ix.Amount, &ix.Decimals)
+		instruction = ix
+	case 16:
+		ix := TokenInitializeAccount{Owner: new(solana.PublicKey), Version: 2}
+		err = read(buf, ix.Owner)
+		instruction = ix
+	case 18:
+		ix := TokenInitializeAccount{Owner: new(solana.PublicKey), Version: 3}
+		err = read(buf, ix.Owner)
+		instruction = ix
+	case 17:
+		instruction = TokenSyncNative{}
+	case 21:
+		// Token-2022 takes the extensions the account will have
+		var ix TokenGetAccountDataSize
+		for buf.Len() > 0 && err == nil {
+			var extensionType uint16
+			err = read(buf, &extensionType)
+			ix.ExtensionTypes = append(ix.ExtensionTypes, extensionType)
+		}
+		instruction = ix
+	case 22:
+		instruction = TokenInitializeImmutableOwner{}
+	case 23:
+		var ix TokenAmountToUiAmount
+		err = read(buf, &ix.Amount)
+		instruction = ix
+	case 24:
+		// The UI amount is the rest of the data
+		value := make([]byte, buf.Len())
+		err = read(buf, value)
+		instruction = TokenUiAmountToAmount{UiAmount: string(value)}
+	default:
+		if !coder.token2022 {
+			return nil, unknownDiscriminator(instructionID)
+		}
+		instruction, err = decodeToken2022Extension(instructionID, buf)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Every field has been read, so anything left over is not a valid instruction
+	if err := expectLength(fmt.Sprintf("Token(%d)", instructionID), buf, 0); err != nil {
+		return nil, err
+	}
+
+	return instruction, nil
+}
+
+// TokenMint is the state of a mint account, with the Token-2022 extensions that let
+// the mint move or tax holders' tokens.
+type TokenMint struct {
+	MintAuthority     *solana.PublicKey
+	Supply            uint64
+	Decimals          uint8
+	IsInitialized     bool
+	FreezeAuthority   *solana.PublicKey
+	Extensions        []uint16
+	TransferFee       *TokenTransferFee
+	PermanentDelegate *solana.PublicKey
+	TransferHook      *solana.PublicKey
+}
+
+// TokenTransferFee is the newer of the two fees a mint keeps, which applies from
+// Epoch on.
+type TokenTransferFee struct {
+	Epoch                  uint64
+	MaximumFee             uint64
+	TransferFeeBasisPoints uint16
+}
+
+// DecodeMint decodes a mint account owned by the coder's program.
+func (coder *SplTokenCoder) DecodeMint(data []byte) (TokenMint, error) {
+	var mint TokenMint
+
+	if len(data) < tokenMintSize {
+		return mint, ErrShortData
+	}
+
+	buf := bytes.NewReader(data[:tokenMintSize])
+
+	var err error
+	if mint.MintAuthority, err = readAccountPubkeyOption(buf); err != nil {
+		return mint, err
+	}
+	if err = read(buf, &mint.Supply, &mint.Decimals, &mint.IsInitialized); err != nil {
+		return mint, err
+	}
+	if mint.FreezeAuthority, err = readAccountPubkeyOption(buf); err != nil {
+		return mint, err
+	}
+
+	if len(data) == tokenMintSize {
+		return mint, nil
+	}
+
+	if !coder.token2022 {
+		return mint, fmt.Errorf("invalid mint size %d", len(data))
+	}
+
+	if len(data) <= tokenAccountSize || data[tokenAccountSize] != tokenAccountTypeMint {
+		return mint, errors.New("not a mint account")
+	}
+
+	return mint, decodeMintExtensions(&mint, data[tokenAccountSize+1:])
+}
+
+// TokenAccount is the start of a token account, which is laid out the same by both
+// programs.
+type TokenAccount struct {
+	Mint   solana.PublicKey
+	Owner  solana.PublicKey
+	Amount uint64
+}
+
+// DecodeTokenAccount decodes the mint, owner and amount of a token account.
+func (coder *SplTokenCoder) DecodeTokenAccount(data []byte) (TokenAccount, error) {
+	var account TokenAccount
+
+	if len(data) < tokenAccountSize {
+		return account, ErrShortData
+	}
+
+	err := read(bytes.NewReader(data), &account)
+	return account, err
+}
+
+// decodeMintExtensions walks the type, length and value entries after the mint.
+func decodeMintExtensions(mint *TokenMint, data []byte) error {
+	buf := bytes.NewReader(data)
+
+	for buf.Len() > 0 {
+		var extensionType, length uint16
+		if err := read(buf, &extensionType, &length); err != nil {
+			return err
+		}
+
+		// The rest of the account is unused once an uninitialized entry is reached
+		if extensionType == 0 {
+			return nil
+		}
+
+		if int(length) > buf.Len() {
+			return ErrShortData
+		}
+
+		value := make([]byte, length)
+		if err := read(buf, value); err != nil {
+			return err
+		}
+
+		mint.Extensions = append(mint.Extensions, extensionType)
+
+		var err error
+		ext := bytes.NewReader(value)
+
+		switch extensionType {
+		case TOKEN_2022_TRANSFER_FEE_CONFIG:
+			// Skip both authorities, the withheld amount and the older fee
+			if _, err = ext.Seek(32+32+8+18, io.SeekStart); err == nil {
+				mint.TransferFee = new(TokenTransferFee)
+				err = read(ext, mint.TransferFee)
+			}
+		case TOKEN_2022_PERMANENT_DELEGATE:
+			mint.PermanentDelegate, err = readOptionalNonZeroPubkey(ext)
+		case TOKEN_2022_TRANSFER_HOOK:
+			// Skip the authority
+			if _, err = ext.Seek(32, io.SeekStart); err == nil {
+				mint.TransferHook, err = readOptionalNonZeroPubkey(ext)
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func decodeToken2022Extension(instructionID byte, buf *bytes.Reader) (interface{}, error) {
+	switch instructionID {
+	case 25:
+		var ix TokenInitializeMintCloseAuthority
+		var err error
+		ix.CloseAuthority, err = readPubkeyOption(buf)
+		return ix, err
+	case TOKEN_2022_TRANSFER_FEE_EXTENSION:
+		return decodeTransferFeeExtension(buf)
+	case 35:
+		var ix TokenInitializePermanentDelegate
+		err := read(buf, &ix.Delegate)
+		return ix, err
+	case TOKEN_2022_TRANSFER_HOOK_EXTENSION:
+		return decodeTransferHookExtension(buf)
+	}
+
+	// Confidential transfers, default account state, memo transfers, interest, CPI
+	// guard, pointers, groups, pausing and later extensions
+	if instructionID >= 27 && instructionID <= 45 {
+		data := make([]byte, buf.Len())
+		err := read(buf, data)
+		return TokenExtension{Extension: instructionID, Data: data}, err
+	}
+
+	return nil, unknownDiscriminator(instructionID)
+}
+
+func decodeTransferFeeExtension(buf *bytes.Reader) (interface{}, error) {
+	var instructionID byte
+	if err := read(buf, &instructionID); err != nil {
+		return nil, err
+	}
+
+	var err error
+
+	switch instructionID {
+	case 0:
+		var ix TokenInitializeTransferFeeConfig
+		if ix.TransferFeeConfigAuthority, err = readPubkeyOption(buf); err != nil {
+			return nil, err
+		}
+		if ix.WithdrawWithheldAuthority, err = readPubkeyOption(buf); err != nil {
+			return nil, err
+		}
+		err = read(buf, &ix.TransferFeeBasisPoints, &ix.MaximumFee)
+		return ix, err
+	case 1:
+		var ix TokenTransferCheckedWithFee
+		err = read(buf, &ix.Amount, &ix.Decimals, &ix.Fee)
+		return ix, err
+	case 2:
+		return TokenWithdrawWithheldTokensFromMint{}, nil
+	case 3:
+		var ix TokenWithdrawWithheldTokensFromAccounts
+		err = read(buf, &ix.NumTokenAccounts)
+		return ix, err
+	case 4:
+		return TokenHarvestWithheldTokensToMint{}, nil
+	case 5:
+		var ix TokenSetTransferFee
+		err = read(buf, &ix.TransferFeeBasisPoints, &ix.MaximumFee)
+		return ix, err
+	default:
+		return nil, unknownDiscriminator(fmt.Sprintf("%d/%d", TOKEN_2022_TRANSFER_FEE_EXTENSION, instructionID))
+	}
+}
+
+func decodeTransferHookExtension(buf *bytes.Reader) (interface{}, error) {
+	var instructionID byte
+	if err := read(buf, &instructionID); err != nil {
+		return nil, err
+	}
+
+	var err error
+
+	switch instructionID {
+	case 0:
+		var ix TokenInitializeTransferHook
+		if ix.Authority, err = readOptionalNonZeroPubkey(buf); err != nil {
+			return nil, err
+		}
+		ix.ProgramId, err = readOptionalNonZeroPubkey(buf)
+		return ix, err
+	case 1:
+		var ix TokenUpdateTransferHook
+		ix.ProgramId, err = readOptionalNonZeroPubkey(buf)
+		return ix, err
+	default:
+		return nil, unknownDiscriminator(fmt.Sprintf("%d/%d", TOKEN_2022_TRANSFER_HOOK_EXTENSION, instructionID))
+	}
+}
+
+// readPubkeyOption reads a token program COption<Pubkey>, a one byte tag followed by
+// the key only when the tag is 1.
+func readPubkeyOption(buf *bytes.Reader) (*solana.PublicKey, error) {
+	var tag uint8
+	if err := read(buf, &tag); err != nil {
+		return nil, err
+	}
+
+	switch tag {
+	case 0:
+		return nil, nil
+	case 1:
+		key := new(solana.PublicKey)
+		return key, read(buf, key)
+	default:
+		return nil, fmt.Errorf("invalid pubkey option tag %d", tag)
+	}
+}
+
+// readOptionalNonZeroPubkey reads a key where all zeroes stands for none.
+func readOptionalNonZeroPubkey(buf *bytes.Reader) (*solana.PublicKey, error) {
+	var key solana.PublicKey
+	if err := read(buf, &key); err != nil {
+		return nil, err
+	}
+
+	if key.IsZero() {
+		return nil, nil
+	}
+	return &key, nil
+}
+
+// readAccountPubkeyOption reads a COption<Pubkey> as stored in accounts, a four byte
+// tag followed by the key whether it is set or not.
+func readAccountPubkeyOption(buf *bytes.Reader) (*solana.PublicKey, error) {
+	var tag uint32
+	var key solana.PublicKey
+	if err := read(buf, &tag, &key); err != nil {
+		return nil, err
+	}
+
+	if tag == 0 {
+		return nil, nil
+	}
+	return &key, nil
+}
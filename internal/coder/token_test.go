@@ -0,0 +1,239 @@
+package coder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestSplTokenDecode(t *testing.T) {
+	authority := testKey(1)
+
+	tests := []decodeTest{
+		{
+			name: "InitializeMint2 with freeze authority",
+			data: le(uint8(20), uint8(6), authority, uint8(1), authority),
+			want: TokenInitializeMint{Decimals: 6, MintAuthority: authority, FreezeAuthority: &authority, V2: true},
+		},
+		{
+			name: "InitializeMint without freeze authority",
+			data: le(uint8(0), uint8(9), authority, uint8(0)),
+			want: TokenInitializeMint{Decimals: 9, MintAuthority: authority},
+		},
+		{
+			name: "InitializeAccount3",
+			data: le(uint8(18), authority),
+			want: TokenInitializeAccount{Owner: &authority, Version: 3},
+		},
+		{
+			name: "Transfer",
+			data: le(uint8(3), uint64(1_000)),
+			want: TokenTransfer{Amount: 1_000},
+		},
+		{
+			name: "SetAuthority removing the mint authority",
+			data: le(uint8(6), uint8(TOKEN_AUTHORITY_MINT_TOKENS), uint8(0)),
+			want: TokenSetAuthority{AuthorityType: TOKEN_AUTHORITY_MINT_TOKENS},
+		},
+		{
+			name: "SetAuthority to a new owner",
+			data: le(uint8(6), uint8(TOKEN_AUTHORITY_ACCOUNT_OWNER), uint8(1), authority),
+			want: TokenSetAuthority{AuthorityType: TOKEN_AUTHORITY_ACCOUNT_OWNER, NewAuthority: &authority},
+		},
+		{
+			name: "Burn",
+			data: le(uint8(8), uint64(500)),
+			want: TokenBurn{Amount: 500},
+		},
+		{
+			name: "TransferChecked",
+			data: le(uint8(12), uint64(1_000), uint8(6)),
+			want: TokenTransferChecked{Amount: 1_000, Decimals: 6},
+		},
+		{
+			name: "CloseAccount",
+			data: le(uint8(9)),
+			want: TokenCloseAccount{},
+		},
+		{
+			name: "UiAmountToAmount",
+			data: append(le(uint8(24)), "1.5"...),
+			want: TokenUiAmountToAmount{UiAmount: "1.5"},
+		},
+		{name: "empty", data: nil, wantErr: ErrShortData},
+		{name: "short Transfer", data: le(uint8(3), uint32(1_000)), wantErr: ErrShortData},
+		{name: "short TransferChecked", data: le(uint8(12), uint64(1_000)), wantErr: ErrShortData},
+		{name: "short SetAuthority key", data: le(uint8(6), uint8(0), uint8(1), uint64(0)), wantErr: ErrShortData},
+		{name: "long Burn", data: le(uint8(8), uint64(500), uint8(0)), wantErr: ErrTrailingData},
+		{name: "CloseAccount with data", data: le(uint8(9), uint8(0)), wantErr: ErrTrailingData},
+		{name: "Token-2022 extension", data: le(uint8(TOKEN_2022_TRANSFER_FEE_EXTENSION), uint8(4)), wantErr: ErrUnknownDiscriminator},
+	}
+
+	runDecodeTests(t, NewSplTokenCoder(), tests)
+}
+
+func TestToken2022Decode(t *testing.T) {
+	authority := testKey(1)
+	hook := testKey(2)
+
+	runDecodeTests(t, NewToken2022Coder(), []decodeTest{
+		{
+			name: "Transfer",
+			data: le(uint8(3), uint64(1_000)),
+			want: TokenTransfer{Amount: 1_000},
+		},
+		{
+			name: "GetAccountDataSize",
+			data: le(uint8(21), uint16(7), uint16(14)),
+			want: TokenGetAccountDataSize{ExtensionTypes: []uint16{7, 14}},
+		},
+		{
+			name: "InitializeTransferFeeConfig",
+			data: le(uint8(TOKEN_2022_TRANSFER_FEE_EXTENSION), uint8(0), uint8(1), authority, uint8(0), uint16(250), uint64(5_000)),
+			want: TokenInitializeTransferFeeConfig{TransferFeeConfigAuthority: &authority, TransferFeeBasisPoints: 250, MaximumFee: 5_000},
+		},
+		{
+			name: "TransferCheckedWithFee",
+			data: le(uint8(TOKEN_2022_TRANSFER_FEE_EXTENSION), uint8(1), uint64(1_000), uint8(6), uint64(25)),
+			want: TokenTransferCheckedWithFee{Amount: 1_000, Decimals: 6, Fee: 25},
+		},
+		{
+			name: "SetTransferFee",
+			data: le(uint8(TOKEN_2022_TRANSFER_FEE_EXTENSION), uint8(5), uint16(1_000), uint64(1)),
+			want: TokenSetTransferFee{TransferFeeBasisPoints: 1_000, MaximumFee: 1},
+		},
+		{
+			name: "InitializePermanentDelegate",
+			data: le(uint8(35), authority),
+			want: TokenInitializePermanentDelegate{Delegate: authority},
+		},
+		{
+			name: "InitializeTransferHook without authority",
+			data: le(uint8(TOKEN_2022_TRANSFER_HOOK_EXTENSION), uint8(0), solana.PublicKey{}, hook),
+			want: TokenInitializeTransferHook{ProgramId: &hook},
+		},
+		{
+			name: "UpdateTransferHook",
+			data: le(uint8(TOKEN_2022_TRANSFER_HOOK_EXTENSION), uint8(1), hook),
+			want: TokenUpdateTransferHook{ProgramId: &hook},
+		},
+		{
+			name: "undecoded extension",
+			data: le(uint8(28), uint8(0), uint8(1)),
+			want: TokenExtension{Extension: 28, Data: []byte{0, 1}},
+		},
+		{name: "short TransferCheckedWithFee", data: le(uint8(TOKEN_2022_TRANSFER_FEE_EXTENSION), uint8(1), uint64(1_000), uint8(6)), wantErr: ErrShortData},
+		{name: "odd GetAccountDataSize", data: le(uint8(21), uint16(7), uint8(0)), wantErr: ErrShortData},
+		{name: "short UpdateTransferHook", data: le(uint8(TOKEN_2022_TRANSFER_HOOK_EXTENSION), uint8(1), uint64(0)), wantErr: ErrShortData},
+		{name: "unknown transfer fee instruction", data: le(uint8(TOKEN_2022_TRANSFER_FEE_EXTENSION), uint8(6)), wantErr: ErrUnknownDiscriminator},
+		{name: "unknown instruction", data: le(uint8(200)), wantErr: ErrUnknownDiscriminator},
+	})
+}
+
+func TestSplTokenDecodeMint(t *testing.T) {
+	authority := testKey(1)
+
+	// InitializeMint2 with a mint authority and no freeze authority, after 1,000
+	// tokens of 6 decimals were minted
+	mint := account(tokenMintSize, map[int]interface{}{
+		0:  uint32(1),
+		4:  authority,
+		36: uint64(1_000_000_000),
+		44: uint8(6),
+		45: true,
+	})
+
+	got, err := NewSplTokenCoder().DecodeMint(mint)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := TokenMint{MintAuthority: &authority, Supply: 1_000_000_000, Decimals: 6, IsInitialized: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeMint() = %+v, want %+v", got, want)
+	}
+
+	if _, err := NewSplTokenCoder().DecodeMint(mint[:81]); !errors.Is(err, ErrShortData) {
+		t.Errorf("short mint: err = %v, want %v", err, ErrShortData)
+	}
+}
+
+func TestToken2022DecodeMintExtensions(t *testing.T) {
+	var (
+		delegate = testKey(1)
+		hook     = testKey(2)
+	)
+
+	// The mint padded to the size of a token account, the account type, then a
+	// transfer fee config, a permanent delegate and a transfer hook
+	extensions := le(
+		uint16(TOKEN_2022_TRANSFER_FEE_CONFIG), uint16(108),
+		testKey(3), testKey(4), uint64(0),
+		uint64(500), uint64(10), uint16(100),
+		uint64(600), uint64(20), uint16(250),
+		uint16(TOKEN_2022_PERMANENT_DELEGATE), uint16(32), delegate,
+		uint16(TOKEN_2022_TRANSFER_HOOK), uint16(64), solana.PublicKey{}, hook,
+	)
+
+	data := account(tokenAccountSize+1+len(extensions), map[int]interface{}{
+		44:               uint8(9),
+		45:               true,
+		tokenAccountSize: uint8(tokenAccountTypeMint),
+	})
+	copy(data[tokenAccountSize+1:], extensions)
+
+	mint, err := NewToken2022Coder().DecodeMint(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []uint16{TOKEN_2022_TRANSFER_FEE_CONFIG, TOKEN_2022_PERMANENT_DELEGATE, TOKEN_2022_TRANSFER_HOOK}; !reflect.DeepEqual(mint.Extensions, want) {
+		t.Errorf("extensions = %v, want %v", mint.Extensions, want)
+	}
+	if want := (TokenTransferFee{Epoch: 600, MaximumFee: 20, TransferFeeBasisPoints: 250}); mint.TransferFee == nil || *mint.TransferFee != want {
+		t.Errorf("transfer fee = %+v, want the newer fee %+v", mint.TransferFee, want)
+	}
+	if mint.PermanentDelegate == nil || *mint.PermanentDelegate != delegate {
+		t.Errorf("permanent delegate = %v, want %s", mint.PermanentDelegate, delegate)
+	}
+	if mint.TransferHook == nil || *mint.TransferHook != hook {
+		t.Errorf("transfer hook = %v, want %s", mint.TransferHook, hook)
+	}
+
+	// An extension running past the end of the account
+	if _, err := NewToken2022Coder().DecodeMint(data[:len(data)-1]); !errors.Is(err, ErrShortData) {
+		t.Errorf("cut extension: err = %v, want %v", err, ErrShortData)
+	}
+
+	// Only Token-2022 mints have extensions
+	if _, err := NewSplTokenCoder().DecodeMint(data); err == nil {
+		t.Error("SPL Token decoded a mint with extensions")
+	}
+}
+
+func TestSplTokenDecodeTokenAccount(t *testing.T) {
+	var (
+		mint  = testKey(1)
+		owner = testKey(2)
+	)
+
+	data := account(tokenAccountSize, map[int]interface{}{
+		0:  mint,
+		32: owner,
+		64: uint64(77),
+	})
+
+	got, err := NewSplTokenCoder().DecodeTokenAccount(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (TokenAccount{Mint: mint, Owner: owner, Amount: 77}); got != want {
+		t.Errorf("DecodeTokenAccount() = %+v, want %+v", got, want)
+	}
+
+	if _, err := NewSplTokenCoder().DecodeTokenAccount(data[:100]); !errors.Is(err, ErrShortData) {
+		t.Errorf("short account: err = %v, want %v", err, ErrShortData)
+	}
+}
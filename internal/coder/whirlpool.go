@@ -0,0 +1,194 @@
+package coder
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	whirlpoolSwap                = AnchorDiscriminator("swap")
+	whirlpoolSwapV2              = AnchorDiscriminator("swap_v2")
+	whirlpoolTwoHopSwap          = AnchorDiscriminator("two_hop_swap")
+	whirlpoolDecreaseLiquidity   = AnchorDiscriminator("decrease_liquidity")
+	whirlpoolDecreaseLiquidityV2 = AnchorDiscriminator("decrease_liquidity_v2")
+	whirlpoolClosePosition       = AnchorDiscriminator("close_position")
+	whirlpoolState               = AnchorAccountDiscriminator("Whirlpool")
+	whirlpoolPosition            = AnchorAccountDiscriminator("Position")
+)
+
+// Account positions in the Whirlpool instructions
+const (
+	WHIRLPOOL_SWAP_AUTHORITY       = 1
+	WHIRLPOOL_SWAP_POOL            = 2
+	WHIRLPOOL_SWAP_V2_AUTHORITY    = 3
+	WHIRLPOOL_SWAP_V2_POOL         = 4
+	WHIRLPOOL_TWO_HOP_AUTHORITY    = 1
+	WHIRLPOOL_TWO_HOP_POOL_ONE     = 2
+	WHIRLPOOL_TWO_HOP_POOL_TWO     = 3
+	WHIRLPOOL_DECREASE_POOL        = 0
+	WHIRLPOOL_DECREASE_POSITION    = 3
+	WHIRLPOOL_DECREASE_V2_POSITION = 5
+	WHIRLPOOL_CLOSE_POSITION       = 2
+)
+
+// WhirlpoolSwap is decoded from both swap and swap_v2. Amount is the input when
+// AmountSpecifiedIsInput is set and the output otherwise, with OtherAmountThreshold
+// bounding the other side.
+type WhirlpoolSwap struct {
+	Amount                 uint64
+	OtherAmountThreshold   uint64
+	SqrtPriceLimit         Uint128
+	AmountSpecifiedIsInput bool
+	AToB                   bool
+	V2                     bool
+}
+
+type WhirlpoolTwoHopSwap struct {
+	Amount                 uint64
+	OtherAmountThreshold   uint64
+	AmountSpecifiedIsInput bool
+	AToBOne                bool
+	AToBTwo                bool
+	SqrtPriceLimitOne      Uint128
+	SqrtPriceLimitTwo      Uint128
+}
+
+// WhirlpoolDecreaseLiquidity is decoded from both decrease_liquidity and
+// decrease_liquidity_v2.
+type WhirlpoolDecreaseLiquidity struct {
+	LiquidityAmount Uint128
+	TokenMinA       uint64
+	TokenMinB       uint64
+	V2              bool
+}
+
+type WhirlpoolClosePosition struct{}
+
+// WhirlpoolState is the leading part of a whirlpool account, up to the B side vault.
+type WhirlpoolState struct {
+	WhirlpoolsConfig solana.PublicKey
+	WhirlpoolBump    [1]byte
+	TickSpacing      uint16
+	TickSpacingSeed  [2]byte
+	FeeRate          uint16
+	ProtocolFeeRate  uint16
+	Liquidity        Uint128
+	SqrtPrice        Uint128
+	TickCurrentIndex int32
+	ProtocolFeeOwedA uint64
+	ProtocolFeeOwedB uint64
+	TokenMintA       solana.PublicKey
+	TokenVaultA      solana.PublicKey
+	FeeGrowthGlobalA Uint128
+	TokenMintB       solana.PublicKey
+	TokenVaultB      solana.PublicKey
+}
+
+// Price returns the price of token A in token B. The whirlpool does not store the
+// mint decimals, so the caller passes them.
+func (state WhirlpoolState) Price(decimalsA int, decimalsB int) *big.Float {
+	return sqrtPriceX64ToPrice(state.SqrtPrice, decimalsA, decimalsB)
+}
+
+// WhirlpoolPosition is the leading part of a position account.
+type WhirlpoolPosition struct {
+	Whirlpool      solana.PublicKey
+	PositionMint   solana.PublicKey
+	Liquidity      Uint128
+	TickLowerIndex int32
+	TickUpperIndex int32
+}
+
+// OrcaWhirlpoolCoder decodes Orca Whirlpool swaps, liquidity removal and state.
+type OrcaWhirlpoolCoder struct{}
+
+func NewOrcaWhirlpoolCoder() *OrcaWhirlpoolCoder {
+	return &OrcaWhirlpoolCoder{}
+}
+
+// Decode decodes the given byte array into an instruction.
+func (coder *OrcaWhirlpoolCoder) Decode(data []byte) (interface{}, error) {
+	decoded, err := decodeWhirlpool(data)
+	if err != nil {
+		recordDecodeFailure(PROGRAM_ORCA_WHIRLPOOL, err)
+	}
+	return decoded, err
+}
+
+func (coder *OrcaWhirlpoolCoder) DecodeWhirlpool(data []byte) (WhirlpoolState, error) {
+	var state WhirlpoolState
+	err := decodeAnchorAccount("WhirlpoolState", whirlpoolState, data, &state)
+	return state, err
+}
+
+func (coder *OrcaWhirlpoolCoder) DecodePosition(data []byte) (WhirlpoolPosition, error) {
+	var position WhirlpoolPosition
+	err := decodeAnchorAccount("WhirlpoolPosition", whirlpoolPosition, data, &position)
+	return position, err
+}
+
+func decodeWhirlpool(data []byte) (interface{}, error) {
+	buf, discriminator, err := readDiscriminator(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch discriminator {
+	case whirlpoolSwap:
+		instruction := WhirlpoolSwap{}
+		if err := expectLength("WhirlpoolSwap", buf, 34); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction.Amount, &instruction.OtherAmountThreshold, &instruction.SqrtPriceLimit, &instruction.AmountSpecifiedIsInput, &instruction.AToB)
+	case whirlpoolSwapV2:
+		instruction := WhirlpoolSwap{V2: true}
+		if err := read(buf, &instruction.Amount, &instruction.OtherAmountThreshold, &instruction.SqrtPriceLimit, &instruction.AmountSpecifiedIsInput, &instruction.AToB); err != nil {
+			return nil, err
+		}
+		return instruction, skipRemainingAccountsInfo("WhirlpoolSwapV2", buf)
+	case whirlpoolTwoHopSwap:
+		var instruction WhirlpoolTwoHopSwap
+		if err := expectLength("WhirlpoolTwoHopSwap", buf, 51); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction)
+	case whirlpoolDecreaseLiquidity:
+		var instruction WhirlpoolDecreaseLiquidity
+		if err := expectLength("WhirlpoolDecreaseLiquidity", buf, 32); err != nil {
+			return nil, err
+		}
+		return instruction, read(buf, &instruction.LiquidityAmount, &instruction.TokenMinA, &instruction.TokenMinB)
+	case whirlpoolDecreaseLiquidityV2:
+		instruction := WhirlpoolDecreaseLiquidity{V2: true}
+		if err := read(buf, &instruction.LiquidityAmount, &instruction.TokenMinA, &instruction.TokenMinB); err != nil {
+			return nil, err
+		}
+		return instruction, skipRemainingAccountsInfo("WhirlpoolDecreaseLiquidityV2", buf)
+	case whirlpoolClosePosition:
+		return WhirlpoolClosePosition{}, expectLength("WhirlpoolClosePosition", buf, 0)
+	default:
+		return nil, unknownDiscriminator(discriminator)
+	}
+}
+
+// skipRemainingAccountsInfo checks the Option<RemainingAccountsInfo> the v2
+// instructions end with: a vector of one byte account type and one byte length.
+func skipRemainingAccountsInfo(name string, buf *bytes.Reader) error {
+	var tag uint8
+	if err := read(buf, &tag); err != nil {
+		return err
+	}
+
+	if tag == 0 {
+		return expectLength(name, buf, 0)
+	}
+
+	var count uint32
+	if err := read(buf, &count); err != nil {
+		return err
+	}
+
+	return expectLength(name, buf, int(count)*2)
+}
@@ -0,0 +1,127 @@
+package coder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOrcaWhirlpoolDecode(t *testing.T) {
+	limit := Uint128{Lo: 4_295_048_016}
+	liquidity := Uint128{Lo: 1_000_000}
+
+	runDecodeTests(t, NewOrcaWhirlpoolCoder(), []decodeTest{
+		{
+			name: "swap",
+			data: append(disc("f8c69e91e17587c8"), le(uint64(1_000), uint64(990), limit, true, false)...),
+			want: WhirlpoolSwap{Amount: 1_000, OtherAmountThreshold: 990, SqrtPriceLimit: limit, AmountSpecifiedIsInput: true},
+		},
+		{
+			name: "swap_v2 without remaining accounts",
+			data: append(disc("2b04ed0b1ac91e62"), le(uint64(1_000), uint64(990), limit, false, true, uint8(0))...),
+			want: WhirlpoolSwap{Amount: 1_000, OtherAmountThreshold: 990, SqrtPriceLimit: limit, AToB: true, V2: true},
+		},
+		{
+			name: "swap_v2 with remaining accounts",
+			data: append(disc("2b04ed0b1ac91e62"), le(uint64(1_000), uint64(990), limit, true, true, uint8(1), uint32(2), uint8(0), uint8(3), uint8(1), uint8(3))...),
+			want: WhirlpoolSwap{Amount: 1_000, OtherAmountThreshold: 990, SqrtPriceLimit: limit, AmountSpecifiedIsInput: true, AToB: true, V2: true},
+		},
+		{
+			name: "two_hop_swap",
+			data: append(disc("c360ed6c44a2dbe6"), le(uint64(1_000), uint64(5), true, true, false, limit, Uint128{Hi: 1})...),
+			want: WhirlpoolTwoHopSwap{Amount: 1_000, OtherAmountThreshold: 5, AmountSpecifiedIsInput: true, AToBOne: true, SqrtPriceLimitOne: limit, SqrtPriceLimitTwo: Uint128{Hi: 1}},
+		},
+		{
+			name: "decrease_liquidity",
+			data: append(disc("a026d06f685b2c01"), le(liquidity, uint64(1), uint64(2))...),
+			want: WhirlpoolDecreaseLiquidity{LiquidityAmount: liquidity, TokenMinA: 1, TokenMinB: 2},
+		},
+		{
+			name: "decrease_liquidity_v2",
+			data: append(disc("3a7fbc3e4f52c460"), le(liquidity, uint64(1), uint64(2), uint8(0))...),
+			want: WhirlpoolDecreaseLiquidity{LiquidityAmount: liquidity, TokenMinA: 1, TokenMinB: 2, V2: true},
+		},
+		{
+			name: "close_position",
+			data: disc("7b86510031446262"),
+			want: WhirlpoolClosePosition{},
+		},
+		{name: "short swap", data: append(disc("f8c69e91e17587c8"), le(uint64(1_000), uint64(990), limit, true)...), wantErr: ErrShortData},
+		{name: "long swap", data: append(disc("f8c69e91e17587c8"), le(uint64(1_000), uint64(990), limit, true, false, uint8(0))...), wantErr: ErrTrailingData},
+		{name: "swap_v2 without the option tag", data: append(disc("2b04ed0b1ac91e62"), le(uint64(1_000), uint64(990), limit, true, true)...), wantErr: ErrShortData},
+		{name: "swap_v2 with fewer slices than counted", data: append(disc("2b04ed0b1ac91e62"), le(uint64(1_000), uint64(990), limit, true, true, uint8(1), uint32(2), uint8(0), uint8(3))...), wantErr: ErrShortData},
+		{name: "short two_hop_swap", data: append(disc("c360ed6c44a2dbe6"), le(uint64(1_000), uint64(5), true, true, false, limit)...), wantErr: ErrShortData},
+		{name: "short decrease_liquidity_v2", data: append(disc("3a7fbc3e4f52c460"), le(liquidity, uint64(1))...), wantErr: ErrShortData},
+		{name: "unknown discriminator", data: disc("2e9cf3760dcdfbb2"), wantErr: ErrUnknownDiscriminator},
+	})
+}
+
+func TestOrcaWhirlpoolDecodeWhirlpool(t *testing.T) {
+	var (
+		mintA  = testKey(10)
+		vaultA = testKey(20)
+		mintB  = testKey(30)
+		vaultB = testKey(40)
+	)
+
+	// Offsets of the on-chain Whirlpool, which is 653 bytes
+	data := account(653, map[int]interface{}{
+		0:   disc("3f95d10ce1806309"),
+		41:  uint16(64),
+		45:  uint16(3_000),
+		49:  Uint128{Lo: 77},
+		65:  Uint128{Hi: 2},
+		81:  int32(-44),
+		101: mintA,
+		133: vaultA,
+		181: mintB,
+		213: vaultB,
+	})
+
+	state, err := NewOrcaWhirlpoolCoder().DecodeWhirlpool(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.TickSpacing != 64 || state.FeeRate != 3_000 || state.Liquidity != (Uint128{Lo: 77}) || state.TickCurrentIndex != -44 {
+		t.Errorf("tick spacing = %d, fee rate = %d, liquidity = %v, tick = %d", state.TickSpacing, state.FeeRate, state.Liquidity.BigInt(), state.TickCurrentIndex)
+	}
+	if state.TokenMintA != mintA || state.TokenVaultA != vaultA || state.TokenMintB != mintB || state.TokenVaultB != vaultB {
+		t.Errorf("keys = %s %s %s %s", state.TokenMintA, state.TokenVaultA, state.TokenMintB, state.TokenVaultB)
+	}
+
+	// A square root price of 2 is a raw price of 4, or 4000 with 9 and 6 decimals
+	if price, _ := state.Price(9, 6).Float64(); price != 4_000 {
+		t.Errorf("Price(9, 6) = %v, want 4000", price)
+	}
+
+	if _, err := NewOrcaWhirlpoolCoder().DecodeWhirlpool(data[:200]); !errors.Is(err, ErrShortData) {
+		t.Errorf("truncated account: err = %v, want %v", err, ErrShortData)
+	}
+}
+
+func TestOrcaWhirlpoolDecodePosition(t *testing.T) {
+	pool := testKey(10)
+
+	data := account(216, map[int]interface{}{
+		0:  disc("aabc8fe47a40f7d0"),
+		8:  pool,
+		40: testKey(20),
+		72: Uint128{Lo: 500},
+		88: int32(-128),
+		92: int32(128),
+	})
+
+	position, err := NewOrcaWhirlpoolCoder().DecodePosition(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if position.Whirlpool != pool || position.Liquidity != (Uint128{Lo: 500}) || position.TickLowerIndex != -128 || position.TickUpperIndex != 128 {
+		t.Errorf("position = %+v", position)
+	}
+
+	// A whirlpool account is not a position
+	if _, err := NewOrcaWhirlpoolCoder().DecodePosition(account(653, map[int]interface{}{0: disc("3f95d10ce1806309")})); !errors.Is(err, ErrUnknownDiscriminator) {
+		t.Errorf("whirlpool account: err = %v, want %v", err, ErrUnknownDiscriminator)
+	}
+}
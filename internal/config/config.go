@@ -1,7 +1,9 @@
 package config
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"io/fs"
 	"math/rand"
 	"os"
 
@@ -13,8 +15,15 @@ import (
 var (
 	WRAPPED_SOL                 = solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
 	TOKEN_PROGRAM_ID            = solana.MustPublicKeyFromBase58("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA")
+	TOKEN_2022_PROGRAM_ID       = solana.MustPublicKeyFromBase58("TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb")
 	ASSOCIATED_TOKEN_PROGRAM_ID = solana.MustPublicKeyFromBase58("ATokenGPvbdGVxr1b2hvZbsiqW5xWH25efTNsLJA8knL")
 	RAYDIUM_AMM_V4              = solana.MustPublicKeyFromBase58("675kPX9MHTjS2zt1qfr1NYHuzeLXfQM9H24wFSUt1Mp8")
+	RAYDIUM_CPMM                = solana.MustPublicKeyFromBase58("CPMMoo8L3F4NbTegBCKVNunggL7H1ZpdTHKxQB5qKP1C")
+	RAYDIUM_CLMM                = solana.MustPublicKeyFromBase58("CAMMCzo5YL8w4VFF8KVHrK22GGUsp5VTaW7grrKgrWqK")
+	METEORA_DLMM                = solana.MustPublicKeyFromBase58("LBUZKhRxPF3XUpBCjp4YzTKgLccjZhTSDM9YuVaPwxo")
+	METEORA_DAMM                = solana.MustPublicKeyFromBase58("Eo7WjKq67rjJQSZxS6z3YkapzY3eMj6Xy8X5EQVn5UaB")
+	ORCA_WHIRLPOOL              = solana.MustPublicKeyFromBase58("whirLbMiicVdio4qvUfM5KAg6Ct8VwpYzGff3uctyCc")
+	PUMP_FUN                    = solana.MustPublicKeyFromBase58("6EF8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P")
 	OPENBOOK_ID                 = solana.MustPublicKeyFromBase58("srmqPvymJeFKQ4zGQed1GFppgkRHL9kaELCbyksJtPX")
 	RAYDIUM_AUTHORITY           = solana.MustPublicKeyFromBase58("5Q544fKrFoe6tsEbD7S8EmxGTJYAKtTVhAW5Q5pge4j1")
 	COMPUTE_PROGRAM             = solana.MustPublicKeyFromBase58("ComputeBudget111111111111111111111111111111")
@@ -26,49 +35,103 @@ var (
 	TA_SIZE                     = 165
 	BUY_METHOD                  = "bloxroute"
 	BLOCKENGINE_URL             = "https://amsterdam.mainnet.block-engine.jito.wtf"
-	GRPC1                       = types.GrpcConfig{
-		Addr:               "lineage-ams.rpcpool.com",
-		Token:              "390cc92f-d182-4400-a829-9524d8a9e23a",
-		InsecureConnection: false,
-	}
-	GRPC2 = types.GrpcConfig{
-		Addr:               "2.57.214.64:4001",
-		Token:              "",
-		InsecureConnection: true,
-	}
+	INCINERATOR                 = solana.MustPublicKeyFromBase58("1nc1nerator11111111111111111111111111111111")
+)
+
+// Programs that lock LP tokens. LP moved into a token account owned by one of them,
+// or by an address derived from one, counts as locked.
+var LP_LOCKERS = []solana.PublicKey{
+	solana.MustPublicKeyFromBase58("LockrWmn6K5twhz3y9w1dQERbmgSaRkfnTeTKbpofwE"), // Raydium liquidity lock
+	solana.MustPublicKeyFromBase58("strmRqUCoQUgGUan5YhzUZa6KqdzwX5L6FpUxfmKg5m"), // Streamflow
+}
+
+// Venue names stored with trades and trackers
+const (
+	DEX_RAYDIUM_AMM_V4 = "raydium_amm_v4"
+	DEX_RAYDIUM_CPMM   = "raydium_cpmm"
+	DEX_RAYDIUM_CLMM   = "raydium_clmm"
+	DEX_METEORA_DLMM   = "meteora_dlmm"
+	DEX_METEORA_DAMM   = "meteora_damm"
+	DEX_ORCA_WHIRLPOOL = "orca_whirlpool"
 )
 
 var (
 	AddressLookupTable solana.PublicKey
-	GrpcAddr           string
-	GrpcToken          string
-	InsecureConnection bool
+	HttpPort           int
 	RedisAddr          string
 	RedisPassword      string
 	RpcHttpUrl         string
 	RpcWsUrl           string
 	MySqlDsn           string
 	MySqlDbName        string
+	Sources            []types.SourceConfig
+	Idls               []IdlConfig
+	Rug                RugConfig
+	QuoteMints         []types.QuoteMint
+	Price              PriceConfig
 )
 
+// InitEnv loads the config file named by CONFIG_FILE (config.yaml by default),
+// applies environment overrides from the process and an optional .env file,
+// and validates the result.
 func InitEnv() error {
-	if err := godotenv.Load(); err != nil {
-		log.Fatalf("Error loading .env file")
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to load .env file: %w", err)
+	}
+
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		path = DEFAULT_CONFIG_FILE
 	}
 
-	GrpcAddr = os.Getenv("GRPC_ENDPOINT")
-	GrpcToken = os.Getenv("GRPC_TOKEN")
-	InsecureConnection = os.Getenv("GRPC_INSECURE") == "true"
-	RedisAddr = os.Getenv("REDIS_ADDR")
-	RedisPassword = os.Getenv("REDIS_PASSWORD")
-	RpcHttpUrl = os.Getenv("RPC_HTTP_URL")
-	RpcWsUrl = os.Getenv("RPC_WS_URL")
-	MySqlDsn = os.Getenv("MYSQL_DSN")
-	MySqlDbName = os.Getenv("MYSQL_DBNAME")
+	cfg, err := LoadFile(path)
+	if err != nil {
+		return err
+	}
+
+	cfg.ApplyEnv()
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config %s:\n%w", path, err)
+	}
+
+	HttpPort = cfg.Http.Port
+	RedisAddr = cfg.Redis.Addr
+	RedisPassword = cfg.Redis.Password
+	RpcHttpUrl = cfg.Rpc.HttpUrl
+	RpcWsUrl = cfg.Rpc.WsUrl
+	MySqlDsn = cfg.MySql.Dsn
+	MySqlDbName = cfg.MySql.DbName
+	Sources = cfg.SourceConfigs()
+	Idls = cfg.Idls
+	Rug = cfg.Rug
+	QuoteMints = cfg.QuoteMints()
+	Price = cfg.Price
 
 	return nil
 }
 
+// DexName returns the venue name of a pool program, or an empty string for
+// programs that are not pool programs.
+func DexName(programId solana.PublicKey) string {
+	switch programId {
+	case RAYDIUM_AMM_V4:
+		return DEX_RAYDIUM_AMM_V4
+	case RAYDIUM_CPMM:
+		return DEX_RAYDIUM_CPMM
+	case RAYDIUM_CLMM:
+		return DEX_RAYDIUM_CLMM
+	case METEORA_DLMM:
+		return DEX_METEORA_DLMM
+	case METEORA_DAMM:
+		return DEX_METEORA_DAMM
+	case ORCA_WHIRLPOOL:
+		return DEX_ORCA_WHIRLPOOL
+	default:
+		return ""
+	}
+}
+
 func GetJitoTipAddress() solana.PublicKey {
 
 	var mainnetTipAccounts = []solana.PublicKey{
@@ -0,0 +1,386 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+const DEFAULT_CONFIG_FILE = "config.yaml"
+
+type FileConfig struct {
+	Http    HttpConfig     `yaml:"http"`
+	Redis   RedisConfig    `yaml:"redis"`
+	MySql   MySqlConfig    `yaml:"mysql"`
+	Rpc     RpcConfig      `yaml:"rpc"`
+	Sources []SourceConfig `yaml:"sources"`
+	Idls    []IdlConfig    `yaml:"idls"`
+	Rug     RugConfig      `yaml:"rug"`
+	Quotes  []QuoteConfig  `yaml:"quote_mints"`
+	Price   PriceConfig    `yaml:"price"`
+}
+
+type HttpConfig struct {
+	Port int `yaml:"port"`
+}
+
+type RedisConfig struct {
+	Addr     string `yaml:"addr"`
+	Password string `yaml:"password"`
+}
+
+type MySqlConfig struct {
+	Dsn    string `yaml:"dsn"`
+	DbName string `yaml:"dbname"`
+}
+
+type RpcConfig struct {
+	HttpUrl string `yaml:"http_url"`
+	WsUrl   string `yaml:"ws_url"`
+}
+
+// RugConfig holds the thresholds withdraws are classified against. A withdraw is a
+// full rug when it leaves the pool at or below Floor SOL, or removes RemovedPct of
+// the reserves or burns LpBurnedPct of the LP supply. Below that, removing or
+// burning PartialPct makes it a partial pull.
+type RugConfig struct {
+	Floor       float64 `yaml:"floor"`
+	RemovedPct  float64 `yaml:"removed_pct"`
+	LpBurnedPct float64 `yaml:"lp_burned_pct"`
+	PartialPct  float64 `yaml:"partial_pct"`
+}
+
+// QuoteConfig is a mint pools can be quoted in. Pools quoted in none of them are
+// skipped.
+type QuoteConfig struct {
+	Mint     string `yaml:"mint"`
+	Symbol   string `yaml:"symbol"`
+	Decimals uint8  `yaml:"decimals"`
+	Usd      bool   `yaml:"usd"`
+}
+
+// PriceConfig is where the SOL/USD price comes from: "jupiter" reads it from Url,
+// "static" always uses SolUsd, and no source leaves amounts unnormalised.
+type PriceConfig struct {
+	Source string        `yaml:"source"`
+	Url    string        `yaml:"url"`
+	SolUsd float64       `yaml:"sol_usd"`
+	Ttl    time.Duration `yaml:"ttl"`
+}
+
+// IdlConfig is an Anchor program decoded from its IDL file.
+type IdlConfig struct {
+	Program string `yaml:"program"`
+	Path    string `yaml:"path"`
+}
+
+type SourceConfig struct {
+	Name     string        `yaml:"name"`
+	Kind     string        `yaml:"kind"`
+	Addr     string        `yaml:"addr"`
+	Token    string        `yaml:"token"`
+	Tls      *bool         `yaml:"tls"`
+	Method   string        `yaml:"method"`
+	RpcUrl   string        `yaml:"rpc_url"`
+	Interval time.Duration `yaml:"interval"`
+	Speed    float64       `yaml:"speed"`
+	Programs []string      `yaml:"programs"`
+}
+
+// LoadFile reads the YAML config at path. ${VAR} references are expanded from
+// the environment so secrets can stay out of the file.
+func LoadFile(path string) (*FileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &FileConfig{
+		Http: HttpConfig{Port: 5000},
+		Rug: RugConfig{
+			Floor:       1,
+			RemovedPct:  80,
+			LpBurnedPct: 80,
+			PartialPct:  20,
+		},
+		Quotes: []QuoteConfig{
+			{Mint: WRAPPED_SOL.String(), Symbol: "SOL", Decimals: 9},
+			{Mint: "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v", Symbol: "USDC", Decimals: 6, Usd: true},
+			{Mint: "Es9vMFrzaCERmJrfqqPtNfusJSHzeR6LXZjJovPHDdKr", Symbol: "USDT", Decimals: 6, Usd: true},
+			{Mint: "USD1ttGY1N17NEEHLmELoaybftRBUSErhqYiQzvEmuB", Symbol: "USD1", Decimals: 6, Usd: true},
+		},
+		Price: PriceConfig{
+			Url: "https://api.jup.ag/price/v2",
+			Ttl: 30 * time.Second,
+		},
+	}
+
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// ApplyEnv overrides file values with any environment variables that are set.
+// A source token can be overridden with SOURCE_<NAME>_TOKEN, and GRPC_ENDPOINT
+// adds an extra gRPC source named "env".
+func (cfg *FileConfig) ApplyEnv() {
+	overrideString(&cfg.Redis.Addr, "REDIS_ADDR")
+	overrideString(&cfg.Redis.Password, "REDIS_PASSWORD")
+	overrideString(&cfg.MySql.Dsn, "MYSQL_DSN")
+	overrideString(&cfg.MySql.DbName, "MYSQL_DBNAME")
+	overrideString(&cfg.Rpc.HttpUrl, "RPC_HTTP_URL")
+	overrideString(&cfg.Rpc.WsUrl, "RPC_WS_URL")
+
+	if port, ok := os.LookupEnv("HTTP_PORT"); ok {
+		// An unparsable port is left as -1 so Validate reports it
+		cfg.Http.Port = -1
+		if value, err := strconv.Atoi(port); err == nil {
+			cfg.Http.Port = value
+		}
+	}
+
+	for i := range cfg.Sources {
+		overrideString(&cfg.Sources[i].Token, sourceEnvKey(cfg.Sources[i].Name, "TOKEN"))
+	}
+
+	if addr := os.Getenv("GRPC_ENDPOINT"); addr != "" {
+		tls := os.Getenv("GRPC_INSECURE") != "true"
+		cfg.Sources = append(cfg.Sources, SourceConfig{
+			Name:  "env",
+			Kind:  "grpc",
+			Addr:  addr,
+			Token: os.Getenv("GRPC_TOKEN"),
+			Tls:   &tls,
+		})
+	}
+}
+
+// Validate checks the whole config and reports every problem found at once.
+func (cfg *FileConfig) Validate() error {
+	var errs []error
+
+	if cfg.Http.Port <= 0 || cfg.Http.Port > 65535 {
+		errs = append(errs, fmt.Errorf("http.port: %d is not a valid port", cfg.Http.Port))
+	}
+
+	if cfg.Redis.Addr == "" {
+		errs = append(errs, errors.New("redis.addr is required"))
+	}
+
+	if cfg.MySql.Dsn == "" {
+		errs = append(errs, errors.New("mysql.dsn is required"))
+	}
+
+	if cfg.MySql.DbName == "" {
+		errs = append(errs, errors.New("mysql.dbname is required"))
+	}
+
+	if cfg.Rpc.HttpUrl == "" {
+		errs = append(errs, errors.New("rpc.http_url is required"))
+	}
+
+	if len(cfg.Sources) == 0 {
+		errs = append(errs, errors.New("sources: at least one source is required"))
+	}
+
+	names := make(map[string]bool)
+	for i, source := range cfg.Sources {
+		field := fmt.Sprintf("sources[%d]", i)
+
+		if source.Name == "" {
+			errs = append(errs, fmt.Errorf("%s.name is required", field))
+		} else if names[source.Name] {
+			errs = append(errs, fmt.Errorf("%s.name: %q is used more than once", field, source.Name))
+		}
+		names[source.Name] = true
+
+		switch source.Kind {
+		case "grpc", "websocket", "polling", "replay":
+		default:
+			errs = append(errs, fmt.Errorf("%s.kind: %q must be one of grpc, websocket, polling, replay", field, source.Kind))
+		}
+
+		if source.Addr == "" {
+			errs = append(errs, fmt.Errorf("%s.addr is required", field))
+		}
+
+		if source.Kind == "websocket" {
+			switch source.Method {
+			case "", "logsSubscribe", "transactionSubscribe":
+			default:
+				errs = append(errs, fmt.Errorf("%s.method: %q must be logsSubscribe or transactionSubscribe", field, source.Method))
+			}
+		}
+
+		if source.Speed < 0 {
+			errs = append(errs, fmt.Errorf("%s.speed must not be negative", field))
+		}
+
+		if source.Interval < 0 {
+			errs = append(errs, fmt.Errorf("%s.interval must not be negative", field))
+		}
+
+		for j, program := range source.Programs {
+			if _, err := solana.PublicKeyFromBase58(program); err != nil {
+				errs = append(errs, fmt.Errorf("%s.programs[%d]: %q is not a valid public key", field, j, program))
+			}
+		}
+	}
+
+	programs := make(map[string]bool)
+	for i, idl := range cfg.Idls {
+		field := fmt.Sprintf("idls[%d]", i)
+
+		if _, err := solana.PublicKeyFromBase58(idl.Program); err != nil {
+			errs = append(errs, fmt.Errorf("%s.program: %q is not a valid public key", field, idl.Program))
+		} else if programs[idl.Program] {
+			errs = append(errs, fmt.Errorf("%s.program: %q is used more than once", field, idl.Program))
+		}
+		programs[idl.Program] = true
+
+		if idl.Path == "" {
+			errs = append(errs, fmt.Errorf("%s.path is required", field))
+		}
+	}
+
+	if cfg.Rug.Floor < 0 {
+		errs = append(errs, errors.New("rug.floor must not be negative"))
+	}
+
+	thresholds := []struct {
+		field string
+		pct   float64
+	}{
+		{"removed_pct", cfg.Rug.RemovedPct},
+		{"lp_burned_pct", cfg.Rug.LpBurnedPct},
+		{"partial_pct", cfg.Rug.PartialPct},
+	}
+
+	for _, threshold := range thresholds {
+		if threshold.pct <= 0 || threshold.pct > 100 {
+			errs = append(errs, fmt.Errorf("rug.%s: %v must be above 0 and at most 100", threshold.field, threshold.pct))
+		}
+	}
+
+	if cfg.Rug.PartialPct > cfg.Rug.RemovedPct || cfg.Rug.PartialPct > cfg.Rug.LpBurnedPct {
+		errs = append(errs, errors.New("rug.partial_pct must not be above removed_pct or lp_burned_pct"))
+	}
+
+	if len(cfg.Quotes) == 0 {
+		errs = append(errs, errors.New("quote_mints: at least one quote mint is required"))
+	}
+
+	quotes := make(map[string]bool)
+	for i, quote := range cfg.Quotes {
+		field := fmt.Sprintf("quote_mints[%d]", i)
+
+		if _, err := solana.PublicKeyFromBase58(quote.Mint); err != nil {
+			errs = append(errs, fmt.Errorf("%s.mint: %q is not a valid public key", field, quote.Mint))
+		} else if quotes[quote.Mint] {
+			errs = append(errs, fmt.Errorf("%s.mint: %q is used more than once", field, quote.Mint))
+		}
+		quotes[quote.Mint] = true
+
+		if quote.Symbol == "" {
+			errs = append(errs, fmt.Errorf("%s.symbol is required", field))
+		}
+	}
+
+	switch cfg.Price.Source {
+	case "":
+	case "jupiter":
+		if cfg.Price.Url == "" {
+			errs = append(errs, errors.New("price.url is required for the jupiter source"))
+		}
+	case "static":
+		if cfg.Price.SolUsd <= 0 {
+			errs = append(errs, errors.New("price.sol_usd must be above 0 for the static source"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("price.source: %q must be jupiter or static", cfg.Price.Source))
+	}
+
+	if cfg.Price.Ttl < 0 {
+		errs = append(errs, errors.New("price.ttl must not be negative"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// SourceConfigs converts the file sources into the form generators.NewSource takes,
+// filling in defaults from the rest of the config.
+func (cfg *FileConfig) SourceConfigs() []types.SourceConfig {
+	sources := make([]types.SourceConfig, len(cfg.Sources))
+	for i, source := range cfg.Sources {
+		programs := source.Programs
+		if len(programs) == 0 {
+			programs = []string{
+				RAYDIUM_AMM_V4.String(),
+				RAYDIUM_CPMM.String(),
+				RAYDIUM_CLMM.String(),
+				METEORA_DLMM.String(),
+				METEORA_DAMM.String(),
+				ORCA_WHIRLPOOL.String(),
+				PUMP_FUN.String(),
+			}
+
+			for _, idl := range cfg.Idls {
+				programs = append(programs, idl.Program)
+			}
+		}
+
+		rpcUrl := source.RpcUrl
+		if rpcUrl == "" {
+			rpcUrl = cfg.Rpc.HttpUrl
+		}
+
+		sources[i] = types.SourceConfig{
+			Name:               source.Name,
+			Kind:               source.Kind,
+			Addr:               source.Addr,
+			Token:              source.Token,
+			InsecureConnection: source.Tls != nil && !*source.Tls,
+			Method:             source.Method,
+			RpcUrl:             rpcUrl,
+			Interval:           source.Interval,
+			Speed:              source.Speed,
+			Programs:           programs,
+		}
+	}
+	return sources
+}
+
+// QuoteMints converts the file quote mints into the form pools are valued with, in
+// the configured order of preference.
+func (cfg *FileConfig) QuoteMints() []types.QuoteMint {
+	quotes := make([]types.QuoteMint, len(cfg.Quotes))
+	for i, quote := range cfg.Quotes {
+		quotes[i] = types.QuoteMint{
+			Mint:     solana.MustPublicKeyFromBase58(quote.Mint),
+			Symbol:   quote.Symbol,
+			Decimals: quote.Decimals,
+			Usd:      quote.Usd,
+		}
+	}
+	return quotes
+}
+
+func overrideString(value *string, key string) {
+	if env, ok := os.LookupEnv(key); ok {
+		*value = env
+	}
+}
+
+func sourceEnvKey(name string, field string) string {
+	key := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(name))
+	return "SOURCE_" + key + "_" + field
+}
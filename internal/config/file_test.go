@@ -0,0 +1,202 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const minimalConfig = `
+redis:
+  addr: localhost:6379
+mysql:
+  dsn: root@tcp(localhost:3306)/
+  dbname: tracker
+rpc:
+  http_url: https://rpc.example.com
+sources:
+  - name: geyser
+    kind: grpc
+    addr: grpc.example.com:443
+    token: file-token
+`
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func loadConfig(t *testing.T, content string) *FileConfig {
+	cfg, err := LoadFile(writeConfig(t, content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestLoadFileDefaults(t *testing.T) {
+	cfg := loadConfig(t, minimalConfig)
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	if cfg.Http.Port != 5000 {
+		t.Errorf("http.port = %d, want 5000", cfg.Http.Port)
+	}
+
+	want := RugConfig{Floor: 1, RemovedPct: 80, LpBurnedPct: 80, PartialPct: 20}
+	if cfg.Rug != want {
+		t.Errorf("rug = %+v, want %+v", cfg.Rug, want)
+	}
+
+	if len(cfg.Quotes) == 0 || cfg.Quotes[0].Mint != WRAPPED_SOL.String() {
+		t.Errorf("quote_mints = %+v, want WSOL first", cfg.Quotes)
+	}
+
+	if cfg.Price.Source != "" || cfg.Price.Ttl != 30*time.Second {
+		t.Errorf("price = %+v, want no source and a 30s ttl", cfg.Price)
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	t.Setenv("REDIS_ADDR", "redis.internal:6379")
+	t.Setenv("MYSQL_DSN", "tracker@tcp(db.internal:3306)/")
+	t.Setenv("HTTP_PORT", "8080")
+	t.Setenv("SOURCE_GEYSER_TOKEN", "env-token")
+	t.Setenv("GRPC_ENDPOINT", "extra.example.com:443")
+	t.Setenv("GRPC_INSECURE", "true")
+
+	cfg := loadConfig(t, minimalConfig)
+	cfg.ApplyEnv()
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	tests := []struct {
+		field string
+		got   any
+		want  any
+	}{
+		{"redis.addr", cfg.Redis.Addr, "redis.internal:6379"},
+		{"mysql.dsn", cfg.MySql.Dsn, "tracker@tcp(db.internal:3306)/"},
+		{"mysql.dbname", cfg.MySql.DbName, "tracker"},
+		{"http.port", cfg.Http.Port, 8080},
+		{"sources[0].token", cfg.Sources[0].Token, "env-token"},
+		{"sources", len(cfg.Sources), 2},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.field, tt.got, tt.want)
+		}
+	}
+
+	if env := cfg.Sources[1]; env.Name != "env" || env.Addr != "extra.example.com:443" || env.Tls == nil || *env.Tls {
+		t.Errorf("env source = %+v, want an insecure gRPC source at extra.example.com:443", env)
+	}
+}
+
+func TestApplyEnvInvalidPort(t *testing.T) {
+	t.Setenv("HTTP_PORT", "http")
+
+	cfg := loadConfig(t, minimalConfig)
+	cfg.ApplyEnv()
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "http.port: -1 is not a valid port") {
+		t.Errorf("Validate() = %v, want the port rejected", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		// want holds every line the joined error must have
+		want []string
+	}{
+		{
+			name: "removed_pct above 100",
+			yaml: "rug:\n  removed_pct: 120\n",
+			want: []string{"rug.removed_pct: 120 must be above 0 and at most 100"},
+		},
+		{
+			name: "zero lp_burned_pct",
+			yaml: "rug:\n  lp_burned_pct: 0\n",
+			want: []string{
+				"rug.lp_burned_pct: 0 must be above 0 and at most 100",
+				"rug.partial_pct must not be above removed_pct or lp_burned_pct",
+			},
+		},
+		{
+			name: "partial above removed",
+			yaml: "rug:\n  removed_pct: 50\n  partial_pct: 60\n",
+			want: []string{"rug.partial_pct must not be above removed_pct or lp_burned_pct"},
+		},
+		{
+			name: "negative floor",
+			yaml: "rug:\n  floor: -1\n",
+			want: []string{"rug.floor must not be negative"},
+		},
+		{
+			name: "invalid quote mint",
+			yaml: "quote_mints:\n  - mint: not-a-mint\n    symbol: BAD\n",
+			want: []string{`quote_mints[0].mint: "not-a-mint" is not a valid public key`},
+		},
+		{
+			name: "duplicate quote mint without symbol",
+			yaml: "quote_mints:\n  - mint: So11111111111111111111111111111111111111112\n    symbol: SOL\n  - mint: So11111111111111111111111111111111111111112\n",
+			want: []string{
+				`quote_mints[1].mint: "So11111111111111111111111111111111111111112" is used more than once`,
+				"quote_mints[1].symbol is required",
+			},
+		},
+		{
+			name: "no quote mints",
+			yaml: "quote_mints: []\n",
+			want: []string{"quote_mints: at least one quote mint is required"},
+		},
+		{
+			name: "every problem at once",
+			yaml: "rug:\n  removed_pct: 0\nprice:\n  source: coingecko\n",
+			want: []string{
+				"rug.removed_pct: 0 must be above 0 and at most 100",
+				"rug.partial_pct must not be above removed_pct or lp_burned_pct",
+				`price.source: "coingecko" must be jupiter or static`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := loadConfig(t, minimalConfig+tt.yaml)
+
+			err := cfg.Validate()
+			if err == nil {
+				t.Fatal("Validate() = nil, want an error")
+			}
+
+			lines := strings.Split(err.Error(), "\n")
+			if len(lines) != len(tt.want) {
+				t.Errorf("Validate() reported %d problems, want %d:\n%v", len(lines), len(tt.want), err)
+			}
+
+			for _, want := range tt.want {
+				found := false
+				for _, line := range lines {
+					found = found || line == want
+				}
+				if !found {
+					t.Errorf("Validate() = %q, missing %q", err, want)
+				}
+			}
+		})
+	}
+}
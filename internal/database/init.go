@@ -2,9 +2,17 @@ package db
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	ER_DUP_FIELDNAME = 1060
+	ER_DUP_KEYNAME   = 1061
 )
 
 type Database struct {
@@ -61,10 +69,20 @@ func (d *Database) CreateDatabaseAndTable() error {
 
 		_, err = d.MysqlClient.Exec(string(c))
 
-		if err != nil {
+		if err != nil && !isAppliedMigration(err) {
 			log.Fatal(err)
 		}
 	}
 
 	return nil
 }
+
+// Migrations run on every start, so an ALTER that adds an existing column is
+// treated as already applied
+func isAppliedMigration(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == ER_DUP_FIELDNAME || mysqlErr.Number == ER_DUP_KEYNAME
+	}
+	return false
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"github.com/gagliardetto/solana-go"
@@ -51,19 +52,32 @@ type MempoolTxn struct {
 	AccountKeys          []string               `json:"accountKeys"`
 	RecentBlockhash      string                 `json:"recentBlockhash"`
 	Instructions         []TxInstruction        `json:"instructions"`
-	InnerInstructions    []*pb.InnerInstruction `json:"innerInstructions"`
+	InnerInstructions    []TxInnerInstructions  `json:"innerInstructions"`
 	AddressTableLookups  []TxAddressTableLookup `json:"addressTableLookups"`
 	PreTokenBalances     []types.TxTokenBalance `json:"preTokenBalances"`
 	PostTokenBalances    []types.TxTokenBalance `json:"postTokenBalances"`
+	PreBalances          []uint64               `json:"preBalances"`
+	PostBalances         []uint64               `json:"postBalances"`
 	ComputeUnitsConsumed uint64                 `json:"computeUnitsConsumed"`
 	Slot                 uint64                 `json:"slot"`
 	Error                string                 `json:"error"`
 }
 
+// TxInstruction is a compiled instruction. StackHeight is the invocation depth of an
+// inner instruction, 2 for one invoked by a top-level instruction, and zero where the
+// source doesn't report it.
 type TxInstruction struct {
 	ProgramIdIndex uint32  `json:"programIdIndex"`
 	Accounts       []uint8 `json:"accounts"`
 	Data           []byte  `json:"data"`
+	StackHeight    uint32  `json:"stackHeight,omitempty"`
+}
+
+// TxInnerInstructions holds the instructions invoked through CPI while executing
+// the top-level instruction at Index.
+type TxInnerInstructions struct {
+	Index        uint32          `json:"index"`
+	Instructions []TxInstruction `json:"instructions"`
 }
 
 type TxAddressTableLookup struct {
@@ -74,11 +88,16 @@ type TxAddressTableLookup struct {
 
 type GeyserResponse struct {
 	MempoolTxns MempoolTxn `json:"mempoolTxns"`
+	// ReceivedAt is when the source received the transaction, before it waited in
+	// the queue for a worker. It is stored in the recording header, not the payload.
+	ReceivedAt time.Time `json:"-"`
 }
 
 type GrpcClient struct {
-	conn   *grpc.ClientConn
-	client pb.GeyserClient
+	conn    *grpc.ClientConn
+	client  pb.GeyserClient
+	health  healthState
+	resumed atomic.Bool
 }
 
 func GrpcConnect(address string, plaintext bool) (*GrpcClient, error) {
@@ -104,7 +123,7 @@ func GrpcConnect(address string, plaintext bool) (*GrpcClient, error) {
 	}
 
 	client := pb.NewGeyserClient(conn)
-	return &GrpcClient{conn, client}, nil
+	return &GrpcClient{conn: conn, client: client}, nil
 }
 
 func (g *GrpcClient) CloseConnection() error {
@@ -115,14 +134,37 @@ func (g *GrpcClient) CloseConnection() error {
 	return nil
 }
 
-func (g *GrpcClient) GrpcSubscribeByAddresses(sourceName string, grpcToken string, accountInclude []string, accountExclude []string, txChannel chan<- GeyserResponse) error {
+func (g *GrpcClient) GrpcSubscribeByAddresses(ctx context.Context, sourceName string, grpcToken string, accountInclude []string, accountExclude []string, txChannel chan<- GeyserResponse) error {
 	if g.client == nil {
 		return errors.New("GRPC not connected")
 	}
 
-	defer close(txChannel)
+	subscriptionJson, err := json.Marshal(newSubscribeRequest(accountInclude, accountExclude, Watched.Accounts()))
+	if err != nil {
+		log.Printf("Failed to marshal subscription request: %v", subscriptionJson)
+		return err
+	}
+	log.Printf("Subscription request: %s", string(subscriptionJson))
+
+	// Set up the subscription request
+	if grpcToken != "" {
+		md := metadata.New(map[string]string{"x-token": grpcToken})
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+
+	// txChannel is shared between sources, so it is never closed here. A failing
+	// stream is retried until ctx is cancelled instead.
+	supervise(ctx, sourceName, &g.health, func(ctx context.Context) (bool, error) {
+		return g.subscribe(ctx, sourceName, accountInclude, accountExclude, txChannel)
+	})
 
-	var subscription pb.SubscribeRequest = pb.SubscribeRequest{
+	return nil
+}
+
+// newSubscribeRequest subscribes to the transactions of accountInclude and, through a
+// filter of their own, to the transactions of the watched accounts.
+func newSubscribeRequest(accountInclude []string, accountExclude []string, watched []string) *pb.SubscribeRequest {
+	subscription := &pb.SubscribeRequest{
 		Slots:        make(map[string]*pb.SubscribeRequestFilterSlots),
 		Blocks:       make(map[string]*pb.SubscribeRequestFilterBlocks),
 		BlocksMeta:   make(map[string]*pb.SubscribeRequestFilterBlocksMeta),
@@ -132,11 +174,9 @@ func (g *GrpcClient) GrpcSubscribeByAddresses(sourceName string, grpcToken strin
 		Commitment:   pb.CommitmentLevel_PROCESSED.Enum(),
 	}
 
-	// subscription.Slots["slots"] = &pb.SubscribeRequestFilterSlots{}
-	subscription.Blocks = make(map[string]*pb.SubscribeRequestFilterBlocks)
-	subscription.BlocksMeta = make(map[string]*pb.SubscribeRequestFilterBlocksMeta)
-	subscription.Accounts = make(map[string]*pb.SubscribeRequestFilterAccounts)
-	subscription.Transactions = make(map[string]*pb.SubscribeRequestFilterTransactions)
+	// Slot updates keep lastSlot moving even when no matching transaction lands,
+	// so a gap after reconnecting reflects the outage and not a quiet program
+	subscription.Slots["slots"] = &pb.SubscribeRequestFilterSlots{}
 
 	// Subscribe to generic transaction stream
 	if len(accountInclude) > 0 {
@@ -148,84 +188,128 @@ func (g *GrpcClient) GrpcSubscribeByAddresses(sourceName string, grpcToken strin
 		}
 	}
 
-	subscriptionJson, err := json.Marshal(&subscription)
-	if err != nil {
-		log.Printf("Failed to marshal subscription request: %v", subscriptionJson)
-		return err
+	// A filter without accounts would match every transaction
+	if len(watched) > 0 {
+		subscription.Transactions["watched"] = &pb.SubscribeRequestFilterTransactions{
+			Vote:           utils.BoolPointer(false),
+			Failed:         utils.BoolPointer(false),
+			AccountInclude: watched,
+		}
 	}
-	log.Printf("Subscription request: %s", string(subscriptionJson))
 
-	// Set up the subscription request
-	ctx := context.Background()
-	if grpcToken != "" {
-		md := metadata.New(map[string]string{"x-token": grpcToken})
-		ctx = metadata.NewOutgoingContext(ctx, md)
-	}
+	return subscription
+}
+
+// subscribe opens a single Subscribe stream and forwards updates until it fails.
+// The subscription is sent again on the stream whenever the watched accounts change.
+// The returned bool reports whether any update was received on the stream.
+func (g *GrpcClient) subscribe(ctx context.Context, sourceName string, accountInclude []string, accountExclude []string, txChannel chan<- GeyserResponse) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
 	stream, err := g.client.Subscribe(ctx,
 		grpc.MaxCallRecvMsgSize(100<<20),
 	)
 
 	if err != nil {
-		log.Fatalf("%v", err)
-		return err
+		return false, err
 	}
 
-	err = stream.Send(&subscription)
+	changed := Watched.Changed()
+	err = stream.Send(newSubscribeRequest(accountInclude, accountExclude, Watched.Accounts()))
 	if err != nil {
-		log.Fatalf("%v", err)
-		return err
+		return false, err
 	}
 
+	// Recv runs on this goroutine, so the stream is only sent to from the other one
+	go func() {
+		err := followWatched(ctx, changed, func(watched []string) error {
+			return stream.Send(newSubscribeRequest(accountInclude, accountExclude, watched))
+		})
+		if err != nil {
+			log.Printf("%s | Failed to update subscription: %v", sourceName, err)
+			cancel()
+		}
+	}()
+
+	g.health.setConnected()
+	g.resumed.Store(g.LastSlot() != 0)
+
+	received := false
 	for {
-		resp, _ := stream.Recv()
+		resp, err := stream.Recv()
 
 		if err == io.EOF {
-			// return
+			return received, errors.New("stream closed by server")
 		}
 
 		if err != nil {
-			log.Printf("Error occurred in receiving update: %v", err)
-			break
+			return received, err
+		}
+
+		received = true
+
+		if resp.GetSlot() != nil {
+			g.observeSlot(sourceName, resp.GetSlot().Slot)
 		}
 
 		if resp.GetTransaction() != nil {
+			g.observeSlot(sourceName, resp.GetTransaction().Slot)
+			send(ctx, txChannel, newGeyserResponse(sourceName, resp))
+		}
+	}
+}
+
+// observeSlot records the highest slot seen and, on the first update after a
+// reconnect, reports how many slots were skipped while the stream was down.
+func (g *GrpcClient) observeSlot(sourceName string, slot uint64) {
+	last := g.LastSlot()
 
-			message := resp.GetTransaction().Transaction.Transaction.Message
-			meta := resp.GetTransaction().Transaction.Meta
+	if g.resumed.CompareAndSwap(true, false) && slot > last+1 {
+		log.Printf("%s | Resumed at slot %d, missed %d slots since %d", sourceName, slot, slot-last-1, last)
+	}
 
-			var errorString string
+	g.health.setReceived(slot)
+}
 
-			if meta.Err != nil {
-				if len(meta.Err.Err) > 9 {
-					relevantByte := meta.Err.Err[9]
-					errorString = fmt.Sprintf("0x%x", relevantByte)
-				} else {
-					errorString = "ERR"
-				}
-			}
+// LastSlot returns the highest slot received from the stream.
+func (g *GrpcClient) LastSlot() uint64 {
+	return g.health.snapshot("").LastSlot
+}
 
-			response := &GeyserResponse{
-				MempoolTxns: MempoolTxn{
-					Source:               sourceName,
-					Signature:            base58.Encode(resp.GetTransaction().Transaction.Signature),
-					AccountKeys:          convertAccountKeys(message.AccountKeys),
-					RecentBlockhash:      base58.Encode(message.RecentBlockhash),
-					Instructions:         convertInstructions(message.Instructions),
-					AddressTableLookups:  convertAddressTableLookups(message.AddressTableLookups),
-					PreTokenBalances:     convertTokenBalances(meta.PreTokenBalances),
-					PostTokenBalances:    convertTokenBalances(meta.PostTokenBalances),
-					ComputeUnitsConsumed: *resp.GetTransaction().Transaction.GetMeta().ComputeUnitsConsumed,
-					Slot:                 resp.GetTransaction().Slot,
-					Error:                errorString,
-				},
-			}
+func newGeyserResponse(sourceName string, resp *pb.SubscribeUpdate) GeyserResponse {
+	message := resp.GetTransaction().Transaction.Transaction.Message
+	meta := resp.GetTransaction().Transaction.Meta
+
+	var errorString string
 
-			txChannel <- *response
+	if meta.Err != nil {
+		if len(meta.Err.Err) > 9 {
+			relevantByte := meta.Err.Err[9]
+			errorString = fmt.Sprintf("0x%x", relevantByte)
+		} else {
+			errorString = "ERR"
 		}
 	}
 
-	return nil
+	return GeyserResponse{
+		MempoolTxns: MempoolTxn{
+			Source:               sourceName,
+			Signature:            base58.Encode(resp.GetTransaction().Transaction.Signature),
+			AccountKeys:          convertAccountKeys(message.AccountKeys),
+			RecentBlockhash:      base58.Encode(message.RecentBlockhash),
+			Instructions:         convertInstructions(message.Instructions),
+			InnerInstructions:    convertInnerInstructions(meta.InnerInstructions),
+			AddressTableLookups:  convertAddressTableLookups(message.AddressTableLookups),
+			PreTokenBalances:     convertTokenBalances(meta.PreTokenBalances),
+			PostTokenBalances:    convertTokenBalances(meta.PostTokenBalances),
+			PreBalances:          meta.PreBalances,
+			PostBalances:         meta.PostBalances,
+			ComputeUnitsConsumed: meta.GetComputeUnitsConsumed(),
+			Slot:                 resp.GetTransaction().Slot,
+			Error:                errorString,
+		},
+	}
 }
 
 func convertAccountKeys(accountKeys [][]byte) []string {
@@ -248,6 +332,27 @@ func convertInstructions(instructions []*pb.CompiledInstruction) []TxInstruction
 	return convertedInstructions
 }
 
+func convertInnerInstructions(innerInstructions []*pb.InnerInstructions) []TxInnerInstructions {
+	convertedInner := make([]TxInnerInstructions, len(innerInstructions))
+	for i, inner := range innerInstructions {
+		instructions := make([]TxInstruction, len(inner.Instructions))
+		for j, instr := range inner.Instructions {
+			instructions[j] = TxInstruction{
+				ProgramIdIndex: instr.ProgramIdIndex,
+				Accounts:       instr.Accounts,
+				Data:           instr.Data,
+				StackHeight:    instr.GetStackHeight(),
+			}
+		}
+
+		convertedInner[i] = TxInnerInstructions{
+			Index:        inner.Index,
+			Instructions: instructions,
+		}
+	}
+	return convertedInner
+}
+
 func convertAddressTableLookups(lookups []*pb.MessageAddressTableLookup) []TxAddressTableLookup {
 	convertedLookups := make([]TxAddressTableLookup, len(lookups))
 	for i, lookup := range lookups {
@@ -264,10 +369,11 @@ func convertTokenBalances(tokenBalances []*pb.TokenBalance) []types.TxTokenBalan
 	convertedBalances := make([]types.TxTokenBalance, len(tokenBalances))
 	for i, balance := range tokenBalances {
 		convertedBalances[i] = types.TxTokenBalance{
-			Mint:    balance.Mint,
-			Owner:   balance.Owner,
-			Amount:  balance.UiTokenAmount.Amount,
-			Decimal: balance.UiTokenAmount.Decimals,
+			AccountIndex: balance.AccountIndex,
+			Mint:         balance.Mint,
+			Owner:        balance.Owner,
+			Amount:       balance.UiTokenAmount.Amount,
+			Decimal:      balance.UiTokenAmount.Decimals,
 		}
 	}
 	return convertedBalances
@@ -0,0 +1,53 @@
+package generators
+
+import (
+	"context"
+	"log"
+)
+
+// GrpcSource streams transactions from a Yellowstone Geyser endpoint.
+type GrpcSource struct {
+	name     string
+	client   *GrpcClient
+	token    string
+	programs []string
+	cancel   context.CancelFunc
+}
+
+func NewGrpcSource(name string, client *GrpcClient, token string, programs []string) *GrpcSource {
+	return &GrpcSource{
+		name:     name,
+		client:   client,
+		token:    token,
+		programs: programs,
+	}
+}
+
+func (s *GrpcSource) Name() string {
+	return s.name
+}
+
+func (s *GrpcSource) Start(ctx context.Context, txChannel chan<- GeyserResponse) error {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	go func() {
+		err := s.client.GrpcSubscribeByAddresses(ctx, s.name, s.token, s.programs, []string{}, txChannel)
+		if err != nil {
+			log.Printf("%s | Error in gRPC subscription: %v", s.name, err)
+		}
+	}()
+
+	return nil
+}
+
+func (s *GrpcSource) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	return s.client.CloseConnection()
+}
+
+func (s *GrpcSource) Health() SourceHealth {
+	return s.client.health.snapshot(s.name)
+}
@@ -0,0 +1,156 @@
+package generators
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+const defaultPollInterval = 2 * time.Second
+
+// PollingSource polls getSignaturesForAddress for each program and watched account
+// and fetches every new transaction over JSON-RPC. It is the slowest feed and meant
+// as a fallback.
+type PollingSource struct {
+	name     string
+	client   *rpc.Client
+	interval time.Duration
+	programs []solana.PublicKey
+	until    map[solana.PublicKey]solana.Signature
+	health   healthState
+	cancel   context.CancelFunc
+}
+
+func NewPollingSource(name string, url string, interval time.Duration, programs []string) (*PollingSource, error) {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	source := &PollingSource{
+		name:     name,
+		client:   rpc.New(url),
+		interval: interval,
+		until:    make(map[solana.PublicKey]solana.Signature),
+	}
+
+	for _, program := range programs {
+		key, err := solana.PublicKeyFromBase58(program)
+		if err != nil {
+			return nil, err
+		}
+		source.programs = append(source.programs, key)
+	}
+
+	return source, nil
+}
+
+func (s *PollingSource) Name() string {
+	return s.name
+}
+
+func (s *PollingSource) Start(ctx context.Context, txChannel chan<- GeyserResponse) error {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			for _, address := range s.addresses() {
+				if err := s.poll(ctx, address, txChannel); err != nil {
+					log.Printf("%s | Failed to poll %s: %v", s.name, address, err)
+					s.health.setError(err)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (s *PollingSource) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	return nil
+}
+
+func (s *PollingSource) Health() SourceHealth {
+	return s.health.snapshot(s.name)
+}
+
+// addresses returns the programs followed by the watched accounts. Accounts no longer
+// watched are forgotten, so watching one again starts from its latest signature.
+func (s *PollingSource) addresses() []solana.PublicKey {
+	addresses := slices.Clone(s.programs)
+	for _, account := range Watched.Accounts() {
+		key, err := solana.PublicKeyFromBase58(account)
+		if err != nil {
+			continue
+		}
+		addresses = append(addresses, key)
+	}
+
+	for address := range s.until {
+		if !slices.Contains(addresses, address) {
+			delete(s.until, address)
+		}
+	}
+
+	return addresses
+}
+
+// poll forwards every signature newer than the last one seen, oldest first. The
+// first poll only records a starting point so history is not replayed.
+func (s *PollingSource) poll(ctx context.Context, address solana.PublicKey, txChannel chan<- GeyserResponse) error {
+	limit := 1000
+	until, seen := s.until[address]
+
+	signatures, err := s.client.GetSignaturesForAddressWithOpts(ctx, address, &rpc.GetSignaturesForAddressOpts{
+		Limit:      &limit,
+		Until:      until,
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.health.setConnected()
+
+	if len(signatures) == 0 {
+		return nil
+	}
+
+	s.until[address] = signatures[0].Signature
+
+	if !seen {
+		return nil
+	}
+
+	for i := len(signatures) - 1; i >= 0; i-- {
+		if signatures[i].Err != nil {
+			continue
+		}
+
+		response, err := fetchRpcTransaction(ctx, s.client, s.name, signatures[i].Signature.String())
+		if err != nil {
+			log.Printf("%s | Failed to fetch transaction %s: %v", s.name, signatures[i].Signature, err)
+			continue
+		}
+
+		s.health.setReceived(response.MempoolTxns.Slot)
+		send(ctx, txChannel, response)
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package generators
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// A recording is a gzip stream that starts with recordingMagic, followed by one
+// record per transaction: the receive time as little-endian int64 unix nanoseconds,
+// the payload length as little-endian uint32, then the JSON encoded GeyserResponse.
+var recordingMagic = []byte("LPRREC01")
+
+const recorderFlushInterval = time.Second
+
+// maxRecordSize bounds the payload length read from a record header, far above any
+// transaction, so a corrupt header fails instead of allocating gigabytes.
+const maxRecordSize = 64 << 20
+
+// ErrTruncatedRecording is returned by Next for a recording cut off mid-record, as
+// left behind by a crash. Every record before the cut has been returned.
+var ErrTruncatedRecording = errors.New("recording is truncated")
+
+type Recorder struct {
+	mu   sync.Mutex
+	file *os.File
+	gz   *gzip.Writer
+	done chan struct{}
+}
+
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(file)
+	if _, err := gz.Write(recordingMagic); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	r := &Recorder{
+		file: file,
+		gz:   gz,
+		done: make(chan struct{}),
+	}
+
+	go r.flushLoop()
+
+	return r, nil
+}
+
+// Record appends response to the recording with the time its source received it,
+// or the current time when it was never stamped.
+func (r *Recorder) Record(response GeyserResponse) error {
+	payload, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	receivedAt := response.ReceivedAt
+	if receivedAt.IsZero() {
+		receivedAt = time.Now()
+	}
+
+	var header [12]byte
+	binary.LittleEndian.PutUint64(header[0:8], uint64(receivedAt.UnixNano()))
+	binary.LittleEndian.PutUint32(header[8:12], uint32(len(payload)))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.gz.Write(header[:]); err != nil {
+		return err
+	}
+
+	_, err = r.gz.Write(payload)
+	return err
+}
+
+func (r *Recorder) Close() error {
+	close(r.done)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.gz.Close(); err != nil {
+		r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
+
+// flushLoop keeps the file readable up to the last second if the process dies
+// without calling Close.
+func (r *Recorder) flushLoop() {
+	ticker := time.NewTicker(recorderFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+			r.mu.Lock()
+			if err := r.gz.Flush(); err != nil {
+				log.Printf("Failed to flush recording: %v", err)
+			}
+			r.mu.Unlock()
+		}
+	}
+}
+
+type RecordingReader struct {
+	file   *os.File
+	gz     *gzip.Reader
+	reader *bufio.Reader
+}
+
+func OpenRecording(path string) (*RecordingReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	reader := bufio.NewReader(gz)
+
+	magic := make([]byte, len(recordingMagic))
+	if _, err := io.ReadFull(reader, magic); err != nil || string(magic) != string(recordingMagic) {
+		gz.Close()
+		file.Close()
+		return nil, fmt.Errorf("%s is not a recording", path)
+	}
+
+	return &RecordingReader{file: file, gz: gz, reader: reader}, nil
+}
+
+// Next returns the next recorded response and when it was received. It returns
+// io.EOF after the last record and ErrTruncatedRecording when the recording ends
+// inside one.
+func (r *RecordingReader) Next() (time.Time, GeyserResponse, error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r.reader, header[:]); err != nil {
+		return time.Time{}, GeyserResponse{}, truncatedOrErr(err)
+	}
+
+	receivedAt := time.Unix(0, int64(binary.LittleEndian.Uint64(header[0:8])))
+
+	size := binary.LittleEndian.Uint32(header[8:12])
+	if size > maxRecordSize {
+		return time.Time{}, GeyserResponse{}, fmt.Errorf("record of %d bytes is too large", size)
+	}
+
+	payload := make([]byte, size)
+
+	if _, err := io.ReadFull(r.reader, payload); err != nil {
+		return time.Time{}, GeyserResponse{}, truncatedOrErr(err)
+	}
+
+	var response GeyserResponse
+	if err := json.Unmarshal(payload, &response); err != nil {
+		return time.Time{}, GeyserResponse{}, err
+	}
+	response.ReceivedAt = receivedAt
+
+	return receivedAt, response, nil
+}
+
+func (r *RecordingReader) Close() error {
+	r.gz.Close()
+	return r.file.Close()
+}
+
+// truncatedOrErr tells a recording that ends inside a record, or inside its gzip
+// stream, from one that ends cleanly after the last record.
+func truncatedOrErr(err error) error {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrTruncatedRecording
+	}
+	return err
+}
@@ -0,0 +1,217 @@
+package generators
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+)
+
+func testResponse(i int) GeyserResponse {
+	return GeyserResponse{
+		MempoolTxns: MempoolTxn{
+			Source:          "grpc",
+			Signature:       fmt.Sprintf("sig-%d", i),
+			AccountKeys:     []string{"11111111111111111111111111111111", "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"},
+			RecentBlockhash: "EkSnNWid2cvwEVnVx9aBqawnmiCNiDgp3gUdkDPTKN1N",
+			Instructions: []TxInstruction{
+				{ProgramIdIndex: 1, Accounts: []uint8{0, 1}, Data: []byte{3, byte(i), 0, 0, 0, 0, 0, 0, 0}},
+			},
+			InnerInstructions: []TxInnerInstructions{
+				{Index: 0, Instructions: []TxInstruction{{ProgramIdIndex: 0, Accounts: []uint8{0}, Data: []byte{2}, StackHeight: 2}}},
+			},
+			PreTokenBalances:  []types.TxTokenBalance{{AccountIndex: 1, Mint: "So11111111111111111111111111111111111111112", Owner: "owner", Amount: "100"}},
+			PostTokenBalances: []types.TxTokenBalance{{AccountIndex: 1, Mint: "So11111111111111111111111111111111111111112", Owner: "owner", Amount: "90"}},
+			PreBalances:       []uint64{5000, 2039280},
+			PostBalances:      []uint64{4000, 2039280},
+			Slot:              uint64(300_000_000 + i),
+		},
+		ReceivedAt: time.Unix(1_700_000_000, int64(i)*1_000_000),
+	}
+}
+
+func TestRecordingRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "round-trip.rec")
+
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const count = 50
+	for i := 0; i < count; i++ {
+		if err := recorder.Record(testResponse(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := OpenRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	for i := 0; i < count; i++ {
+		receivedAt, response, err := reader.Next()
+		if err != nil {
+			t.Fatalf("record %d: %v", i, err)
+		}
+
+		want := testResponse(i)
+		if !receivedAt.Equal(want.ReceivedAt) || !response.ReceivedAt.Equal(want.ReceivedAt) {
+			t.Errorf("record %d: received at %s, want %s", i, receivedAt, want.ReceivedAt)
+		}
+		if !reflect.DeepEqual(response.MempoolTxns, want.MempoolTxns) {
+			t.Errorf("record %d: got %+v, want %+v", i, response.MempoolTxns, want.MempoolTxns)
+		}
+	}
+
+	if _, _, err := reader.Next(); err != io.EOF {
+		t.Errorf("after the last record: err = %v, want io.EOF", err)
+	}
+}
+
+// writeRecording writes a recording of the given bytes after the magic, gzipped
+// and closed, so only its records are malformed.
+func writeRecording(t *testing.T, records []byte) string {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write(recordingMagic)
+	gz.Write(records)
+	gz.Close()
+
+	path := filepath.Join(t.TempDir(), "test.rec")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func recordBytes(t *testing.T, response GeyserResponse) []byte {
+	path := filepath.Join(t.TempDir(), "one.rec")
+
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Record(response); err != nil {
+		t.Fatal(err)
+	}
+	recorder.Close()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data[len(recordingMagic):]
+}
+
+func TestRecordingTruncated(t *testing.T) {
+	record := recordBytes(t, testResponse(1))
+
+	oversized := make([]byte, 12)
+	binary.LittleEndian.PutUint32(oversized[8:12], maxRecordSize+1)
+
+	tests := []struct {
+		name    string
+		records []byte
+		// complete is the number of records read before the error
+		complete int
+		wantErr  error
+	}{
+		{name: "cut inside the header", records: append(append([]byte{}, record...), record[:6]...), complete: 1, wantErr: ErrTruncatedRecording},
+		{name: "cut inside the payload", records: append(append([]byte{}, record...), record[:len(record)-5]...), complete: 1, wantErr: ErrTruncatedRecording},
+		{name: "oversized payload length", records: oversized, complete: 0},
+		{name: "corrupt payload", records: append(record[:12:12], bytes.Repeat([]byte{'x'}, len(record)-12)...), complete: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := OpenRecording(writeRecording(t, tt.records))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer reader.Close()
+
+			for i := 0; i < tt.complete; i++ {
+				if _, _, err := reader.Next(); err != nil {
+					t.Fatalf("record %d: %v", i, err)
+				}
+			}
+
+			_, _, err = reader.Next()
+			if err == nil || err == io.EOF {
+				t.Fatalf("err = %v, want an error", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// A file cut off inside its gzip stream, as a crashed recorder leaves it, fails
+// at the cut rather than ending as if the recording were complete.
+func TestRecordingCutFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cut.rec")
+
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		recorder.Record(testResponse(i))
+	}
+	recorder.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data[:len(data)*2/3], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := OpenRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	for i := 0; ; i++ {
+		_, response, err := reader.Next()
+		if err == io.EOF {
+			t.Fatalf("recording ended cleanly after %d records", i)
+		}
+		if err != nil {
+			break
+		}
+		if want := fmt.Sprintf("sig-%d", i); response.MempoolTxns.Signature != want {
+			t.Fatalf("record %d: signature %s, want %s", i, response.MempoolTxns.Signature, want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package generators
+
+import (
+	"context"
+	"io"
+	"log"
+	"time"
+)
+
+// ReplaySource feeds a recording back into the pipeline. A speed of 1 keeps the
+// original timing, N replays N times faster and 0 sends as fast as possible.
+type ReplaySource struct {
+	name   string
+	path   string
+	speed  float64
+	health healthState
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewReplaySource(name string, path string, speed float64) *ReplaySource {
+	return &ReplaySource{
+		name:  name,
+		path:  path,
+		speed: speed,
+		done:  make(chan struct{}),
+	}
+}
+
+func (s *ReplaySource) Name() string {
+	return s.name
+}
+
+func (s *ReplaySource) Start(ctx context.Context, txChannel chan<- GeyserResponse) error {
+	reader, err := OpenRecording(s.path)
+	if err != nil {
+		return err
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.health.setConnected()
+
+	go func() {
+		defer close(s.done)
+		defer reader.Close()
+
+		count, err := s.replay(ctx, reader, txChannel)
+		if err != nil {
+			log.Printf("%s | Replay of %s stopped after %d transactions: %v", s.name, s.path, count, err)
+			s.health.setError(err)
+			return
+		}
+
+		log.Printf("%s | Replay of %s finished, %d transactions", s.name, s.path, count)
+		s.health.setError(nil)
+	}()
+
+	return nil
+}
+
+func (s *ReplaySource) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	return nil
+}
+
+func (s *ReplaySource) Health() SourceHealth {
+	return s.health.snapshot(s.name)
+}
+
+// Done is closed once every record has been sent or the replay is stopped.
+func (s *ReplaySource) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *ReplaySource) replay(ctx context.Context, reader *RecordingReader, txChannel chan<- GeyserResponse) (int, error) {
+	var (
+		count     int
+		firstSeen time.Time
+		startedAt = time.Now()
+	)
+
+	for {
+		receivedAt, response, err := reader.Next()
+		if err == io.EOF {
+			return count, nil
+		}
+
+		if err != nil {
+			return count, err
+		}
+
+		if firstSeen.IsZero() {
+			firstSeen = receivedAt
+		}
+
+		if s.speed > 0 {
+			offset := time.Duration(float64(receivedAt.Sub(firstSeen)) / s.speed)
+			select {
+			case <-ctx.Done():
+				return count, ctx.Err()
+			case <-time.After(time.Until(startedAt.Add(offset))):
+			}
+		}
+
+		select {
+		case txChannel <- response:
+		case <-ctx.Done():
+			return count, ctx.Err()
+		}
+
+		count++
+		s.health.setReceived(response.MempoolTxns.Slot)
+	}
+}
@@ -0,0 +1,140 @@
+package generators
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+)
+
+// newRpcGeyserResponse converts a transaction returned by JSON-RPC (or a
+// websocket notification) into the same shape the gRPC stream produces.
+func newRpcGeyserResponse(sourceName string, slot uint64, tx *solana.Transaction, meta *rpc.TransactionMeta) GeyserResponse {
+	message := tx.Message
+
+	var signature string
+	if len(tx.Signatures) > 0 {
+		signature = tx.Signatures[0].String()
+	}
+
+	accountKeys := make([]string, len(message.AccountKeys))
+	for i, key := range message.AccountKeys {
+		accountKeys[i] = key.String()
+	}
+
+	instructions := make([]TxInstruction, len(message.Instructions))
+	for i, instr := range message.Instructions {
+		instructions[i] = convertRpcInstruction(instr)
+	}
+
+	lookups := make([]TxAddressTableLookup, len(message.AddressTableLookups))
+	for i, lookup := range message.AddressTableLookups {
+		lookups[i] = TxAddressTableLookup{
+			AccountKey:      lookup.AccountKey.String(),
+			WritableIndexes: lookup.WritableIndexes,
+			ReadonlyIndexes: lookup.ReadonlyIndexes,
+		}
+	}
+
+	response := GeyserResponse{
+		MempoolTxns: MempoolTxn{
+			Source:              sourceName,
+			Signature:           signature,
+			AccountKeys:         accountKeys,
+			RecentBlockhash:     message.RecentBlockhash.String(),
+			Instructions:        instructions,
+			AddressTableLookups: lookups,
+			Slot:                slot,
+		},
+	}
+
+	if meta != nil {
+		response.MempoolTxns.PreTokenBalances = convertRpcTokenBalances(meta.PreTokenBalances)
+		response.MempoolTxns.PostTokenBalances = convertRpcTokenBalances(meta.PostTokenBalances)
+		response.MempoolTxns.PreBalances = meta.PreBalances
+		response.MempoolTxns.PostBalances = meta.PostBalances
+		response.MempoolTxns.Error = rpcErrorString(meta.Err)
+
+		for _, inner := range meta.InnerInstructions {
+			instructions := make([]TxInstruction, len(inner.Instructions))
+			for i, instr := range inner.Instructions {
+				instructions[i] = convertRpcInstruction(instr)
+			}
+
+			response.MempoolTxns.InnerInstructions = append(response.MempoolTxns.InnerInstructions, TxInnerInstructions{
+				Index:        uint32(inner.Index),
+				Instructions: instructions,
+			})
+		}
+
+		if meta.ComputeUnitsConsumed != nil {
+			response.MempoolTxns.ComputeUnitsConsumed = *meta.ComputeUnitsConsumed
+		}
+	}
+
+	return response
+}
+
+func convertRpcInstruction(instr solana.CompiledInstruction) TxInstruction {
+	accounts := make([]uint8, len(instr.Accounts))
+	for i, idx := range instr.Accounts {
+		accounts[i] = uint8(idx)
+	}
+
+	return TxInstruction{
+		ProgramIdIndex: uint32(instr.ProgramIDIndex),
+		Accounts:       accounts,
+		Data:           instr.Data,
+	}
+}
+
+func convertRpcTokenBalances(tokenBalances []rpc.TokenBalance) []types.TxTokenBalance {
+	convertedBalances := make([]types.TxTokenBalance, len(tokenBalances))
+	for i, balance := range tokenBalances {
+		convertedBalances[i] = types.TxTokenBalance{
+			AccountIndex: uint32(balance.AccountIndex),
+			Mint:         balance.Mint.String(),
+		}
+
+		if balance.Owner != nil {
+			convertedBalances[i].Owner = balance.Owner.String()
+		}
+
+		if balance.UiTokenAmount != nil {
+			convertedBalances[i].Amount = balance.UiTokenAmount.Amount
+			convertedBalances[i].Decimal = uint32(balance.UiTokenAmount.Decimals)
+		}
+	}
+	return convertedBalances
+}
+
+// rpcErrorString mirrors the gRPC error format: the low byte of a custom
+// program error code, or "ERR" for any other failure.
+func rpcErrorString(txErr interface{}) string {
+	if txErr == nil {
+		return ""
+	}
+
+	errMap, ok := txErr.(map[string]interface{})
+	if !ok {
+		return "ERR"
+	}
+
+	instructionErr, ok := errMap["InstructionError"].([]interface{})
+	if !ok || len(instructionErr) < 2 {
+		return "ERR"
+	}
+
+	custom, ok := instructionErr[1].(map[string]interface{})
+	if !ok {
+		return "ERR"
+	}
+
+	code, ok := custom["Custom"].(float64)
+	if !ok {
+		return "ERR"
+	}
+
+	return fmt.Sprintf("0x%x", uint8(uint32(code)))
+}
@@ -0,0 +1,150 @@
+package generators
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+)
+
+const (
+	SourceGrpc      = "grpc"
+	SourceWebsocket = "websocket"
+	SourcePolling   = "polling"
+	SourceReplay    = "replay"
+)
+
+const (
+	minReconnectBackoff = 500 * time.Millisecond
+	maxReconnectBackoff = 30 * time.Second
+)
+
+// Source is a transaction feed that pushes GeyserResponse into a channel shared
+// with every other configured source.
+type Source interface {
+	Name() string
+	// Start begins streaming in the background and returns once the source is running.
+	Start(ctx context.Context, txChannel chan<- GeyserResponse) error
+	Stop() error
+	Health() SourceHealth
+}
+
+type SourceHealth struct {
+	Name         string    `json:"name"`
+	Connected    bool      `json:"connected"`
+	LastSlot     uint64    `json:"last_slot"`
+	LastReceived time.Time `json:"last_received"`
+	LastError    string    `json:"last_error"`
+}
+
+// NewSource builds the source described by cfg.
+func NewSource(cfg types.SourceConfig) (Source, error) {
+	switch cfg.Kind {
+	case SourceGrpc:
+		client, err := GrpcConnect(cfg.Addr, cfg.InsecureConnection)
+		if err != nil {
+			return nil, err
+		}
+		return NewGrpcSource(cfg.Name, client, cfg.Token, cfg.Programs), nil
+	case SourceWebsocket:
+		return NewWSSource(cfg.Name, cfg.Addr, cfg.Token, cfg.Method, cfg.RpcUrl, cfg.Programs)
+	case SourcePolling:
+		return NewPollingSource(cfg.Name, cfg.Addr, cfg.Interval, cfg.Programs)
+	case SourceReplay:
+		return NewReplaySource(cfg.Name, cfg.Addr, cfg.Speed), nil
+	default:
+		return nil, fmt.Errorf("unknown source kind %q", cfg.Kind)
+	}
+}
+
+type healthState struct {
+	mu     sync.RWMutex
+	health SourceHealth
+}
+
+func (h *healthState) setConnected() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.health.Connected = true
+}
+
+func (h *healthState) setError(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.health.Connected = false
+	if err != nil {
+		h.health.LastError = err.Error()
+	}
+}
+
+func (h *healthState) setReceived(slot uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.health.LastReceived = time.Now()
+	if slot > h.health.LastSlot {
+		h.health.LastSlot = slot
+	}
+}
+
+func (h *healthState) snapshot(name string) SourceHealth {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	health := h.health
+	health.Name = name
+	return health
+}
+
+// supervise runs attempt until ctx is cancelled, waiting with exponential backoff
+// and jitter between failures. The backoff resets after an attempt that received data.
+func supervise(ctx context.Context, name string, health *healthState, attempt func(context.Context) (bool, error)) {
+	backoff := minReconnectBackoff
+	for {
+		received, err := attempt(ctx)
+		health.setError(err)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if received {
+			backoff = minReconnectBackoff
+		}
+
+		wait := withJitter(backoff)
+		log.Printf("%s | Stream interrupted at slot %d: %v. Reconnecting in %s", name, health.snapshot(name).LastSlot, err, wait)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		backoff = min(backoff*2, maxReconnectBackoff)
+	}
+}
+
+func withJitter(d time.Duration) time.Duration {
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// send forwards a response unless the source is being stopped. Responses not yet
+// stamped are stamped with the current time.
+func send(ctx context.Context, txChannel chan<- GeyserResponse, response GeyserResponse) {
+	if response.ReceivedAt.IsZero() {
+		response.ReceivedAt = time.Now()
+	}
+
+	select {
+	case txChannel <- response:
+	case <-ctx.Done():
+	}
+}
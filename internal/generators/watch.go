@@ -0,0 +1,131 @@
+package generators
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Watched holds the accounts every source streams transactions of on top of its
+// programs, such as the mints of tracked tokens. Their instructions are sent by the
+// token programs, which are too busy to subscribe to whole.
+var Watched = NewWatchlist()
+
+// watchSettle batches accounts added close together into a single resubscription.
+const watchSettle = time.Second
+
+// Watchlist is a set of accounts that changes while the sources are running. An
+// account can be kept for a limited time, after which it is dropped from the set.
+type Watchlist struct {
+	mu       sync.Mutex
+	accounts map[string]time.Time
+	changed  chan struct{}
+}
+
+func NewWatchlist() *Watchlist {
+	return &Watchlist{
+		accounts: make(map[string]time.Time),
+		changed:  make(chan struct{}),
+	}
+}
+
+// Add watches account for ttl, or until it is removed when ttl is 0. Adding an
+// account that is already watched only extends how long it is kept.
+func (w *Watchlist) Add(account string, ttl time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var expiry time.Time
+	if ttl > 0 {
+		expiry = time.Now().Add(ttl)
+	}
+
+	current, watched := w.accounts[account]
+	if watched && w.isExpired(current) {
+		watched = false
+	}
+
+	switch {
+	case !watched:
+		w.accounts[account] = expiry
+		w.notify()
+	case current.IsZero():
+	case expiry.IsZero() || expiry.After(current):
+		w.accounts[account] = expiry
+	}
+}
+
+// Remove stops watching account.
+func (w *Watchlist) Remove(account string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, watched := w.accounts[account]; !watched {
+		return
+	}
+
+	delete(w.accounts, account)
+	w.notify()
+}
+
+// Accounts returns the watched accounts in a stable order, dropping the ones that
+// expired.
+func (w *Watchlist) Accounts() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	accounts := make([]string, 0, len(w.accounts))
+	for account, expiry := range w.accounts {
+		if w.isExpired(expiry) {
+			delete(w.accounts, account)
+			continue
+		}
+		accounts = append(accounts, account)
+	}
+
+	sort.Strings(accounts)
+	return accounts
+}
+
+// Changed returns a channel that is closed the next time an account is added or
+// removed. Expired accounts are only left out of the next call to Accounts.
+func (w *Watchlist) Changed() <-chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.changed
+}
+
+func (w *Watchlist) notify() {
+	close(w.changed)
+	w.changed = make(chan struct{})
+}
+
+func (w *Watchlist) isExpired(expiry time.Time) bool {
+	return !expiry.IsZero() && time.Now().After(expiry)
+}
+
+// followWatched calls update with the watched accounts every time they change until
+// ctx is done or update fails. changed is taken from Changed before the accounts the
+// caller already subscribed to were read, so no change in between is missed.
+func followWatched(ctx context.Context, changed <-chan struct{}, update func(accounts []string) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watchSettle):
+		}
+
+		changed = Watched.Changed()
+		if err := update(Watched.Accounts()); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package generators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	WSLogsSubscribe        = "logsSubscribe"
+	WSTransactionSubscribe = "transactionSubscribe"
+)
+
+// maxConcurrentFetches bounds the getTransaction calls in flight for logsSubscribe.
+// Once it is reached the source stops reading notifications until a fetch finishes.
+const maxConcurrentFetches = 8
+
+// WSSource streams transactions over a websocket. With transactionSubscribe the
+// notification carries the full transaction; with logsSubscribe only the
+// signature is pushed and the transaction is fetched over JSON-RPC.
+type WSSource struct {
+	name     string
+	url      string
+	auth     string
+	method   string
+	programs []string
+	rpc      *rpc.Client
+	fetches  chan struct{}
+	health   healthState
+	cancel   context.CancelFunc
+}
+
+type wsNotification struct {
+	Method string `json:"method"`
+	Params struct {
+		Result json.RawMessage `json:"result"`
+	} `json:"params"`
+}
+
+type wsLogsResult struct {
+	Context struct {
+		Slot uint64 `json:"slot"`
+	} `json:"context"`
+	Value struct {
+		Signature string      `json:"signature"`
+		Err       interface{} `json:"err"`
+	} `json:"value"`
+}
+
+type wsTransactionResult struct {
+	Transaction rpc.TransactionWithMeta `json:"transaction"`
+	Signature   string                  `json:"signature"`
+	Slot        uint64                  `json:"slot"`
+}
+
+func NewWSSource(name string, url string, auth string, method string, rpcUrl string, programs []string) (*WSSource, error) {
+	if method == "" {
+		method = WSLogsSubscribe
+	}
+
+	if method != WSLogsSubscribe && method != WSTransactionSubscribe {
+		return nil, fmt.Errorf("unsupported websocket method %q", method)
+	}
+
+	if method == WSLogsSubscribe && rpcUrl == "" {
+		return nil, fmt.Errorf("%s requires an RPC URL to fetch transactions", method)
+	}
+
+	source := &WSSource{
+		name:     name,
+		url:      url,
+		auth:     auth,
+		method:   method,
+		programs: programs,
+		fetches:  make(chan struct{}, maxConcurrentFetches),
+	}
+
+	if rpcUrl != "" {
+		source.rpc = rpc.New(rpcUrl)
+	}
+
+	return source, nil
+}
+
+func (s *WSSource) Name() string {
+	return s.name
+}
+
+func (s *WSSource) Start(ctx context.Context, txChannel chan<- GeyserResponse) error {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	go supervise(ctx, s.name, &s.health, func(ctx context.Context) (bool, error) {
+		return s.stream(ctx, txChannel)
+	})
+
+	return nil
+}
+
+func (s *WSSource) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	return nil
+}
+
+func (s *WSSource) Health() SourceHealth {
+	return s.health.snapshot(s.name)
+}
+
+func (s *WSSource) stream(ctx context.Context, txChannel chan<- GeyserResponse) (bool, error) {
+	client, err := NewWSClient(s.url, s.auth)
+	if err != nil {
+		return false, err
+	}
+
+	// Closing the connection is what unblocks ReadMessage when the source stops
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		client.Conn.Close()
+	}()
+
+	changed := Watched.Changed()
+	watched := Watched.Accounts()
+
+	subscribed := make(map[string]bool)
+	for _, account := range watched {
+		subscribed[account] = true
+	}
+
+	nextId := 0
+	subscribe := func(accounts []string) error {
+		for _, request := range s.subscribeRequests(accounts) {
+			nextId++
+			requestData, err := json.Marshal(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      nextId,
+				"method":  s.method,
+				"params":  request,
+			})
+			if err != nil {
+				return err
+			}
+
+			// Written to the connection directly, as reconnecting would swap it under
+			// the reader. A failed write ends the stream and supervise reconnects.
+			if err := client.Conn.WriteMessage(websocket.TextMessage, requestData); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := subscribe(append(slices.Clone(s.programs), watched...)); err != nil {
+		return false, err
+	}
+
+	// Accounts added to the watchlist are subscribed to on the open connection. The
+	// ones dropped from it stay subscribed until the source reconnects.
+	watchCtx, stopWatching := context.WithCancel(ctx)
+	defer stopWatching()
+	go func() {
+		err := followWatched(watchCtx, changed, func(watched []string) error {
+			var added []string
+			for _, account := range watched {
+				if !subscribed[account] {
+					subscribed[account] = true
+					added = append(added, account)
+				}
+			}
+
+			if len(added) == 0 {
+				return nil
+			}
+			return subscribe(added)
+		})
+		if err != nil {
+			log.Printf("%s | Failed to subscribe to watched accounts: %v", s.name, err)
+			client.Conn.Close()
+		}
+	}()
+
+	s.health.setConnected()
+
+	received := false
+	for {
+		_, message, err := client.Conn.ReadMessage()
+		if err != nil {
+			return received, err
+		}
+
+		received = true
+		s.handleMessage(ctx, message, txChannel)
+	}
+}
+
+// subscribeRequests returns the params of every subscription to send for accounts.
+// logsSubscribe only accepts a single address per subscription.
+func (s *WSSource) subscribeRequests(accounts []string) [][]interface{} {
+	if s.method == WSTransactionSubscribe {
+		return [][]interface{}{{
+			map[string]interface{}{
+				"accountInclude": accounts,
+				"vote":           false,
+				"failed":         false,
+			},
+			map[string]interface{}{
+				"commitment":                     "processed",
+				"encoding":                       "base64",
+				"transactionDetails":             "full",
+				"maxSupportedTransactionVersion": 0,
+			},
+		}}
+	}
+
+	var requests [][]interface{}
+	for _, account := range accounts {
+		requests = append(requests, []interface{}{
+			map[string]interface{}{"mentions": []string{account}},
+			map[string]interface{}{"commitment": "confirmed"},
+		})
+	}
+	return requests
+}
+
+func (s *WSSource) handleMessage(ctx context.Context, message []byte, txChannel chan<- GeyserResponse) {
+	var notification wsNotification
+	if err := json.Unmarshal(message, &notification); err != nil {
+		log.Printf("%s | Failed to unmarshal message: %v", s.name, err)
+		return
+	}
+
+	switch notification.Method {
+	case "transactionNotification":
+		var result wsTransactionResult
+		if err := json.Unmarshal(notification.Params.Result, &result); err != nil {
+			log.Printf("%s | Failed to unmarshal transaction: %v", s.name, err)
+			return
+		}
+
+		tx, err := result.Transaction.GetTransaction()
+		if err != nil {
+			log.Printf("%s | Failed to decode transaction %s: %v", s.name, result.Signature, err)
+			return
+		}
+
+		s.health.setReceived(result.Slot)
+		send(ctx, txChannel, newRpcGeyserResponse(s.name, result.Slot, tx, result.Transaction.Meta))
+	case "logsNotification":
+		var result wsLogsResult
+		if err := json.Unmarshal(notification.Params.Result, &result); err != nil {
+			log.Printf("%s | Failed to unmarshal logs: %v", s.name, err)
+			return
+		}
+
+		s.health.setReceived(result.Context.Slot)
+
+		if result.Value.Err != nil {
+			return
+		}
+
+		select {
+		case s.fetches <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		receivedAt := time.Now()
+		go func() {
+			defer func() { <-s.fetches }()
+			s.fetchTransaction(ctx, result.Value.Signature, receivedAt, txChannel)
+		}()
+	}
+}
+
+// fetchTransaction forwards the transaction stamped with when its notification
+// arrived, so the time spent fetching it is not mistaken for stream latency.
+func (s *WSSource) fetchTransaction(ctx context.Context, signature string, receivedAt time.Time, txChannel chan<- GeyserResponse) {
+	response, err := fetchRpcTransaction(ctx, s.rpc, s.name, signature)
+	if err != nil {
+		log.Printf("%s | Failed to fetch transaction %s: %v", s.name, signature, err)
+		return
+	}
+	response.ReceivedAt = receivedAt
+
+	send(ctx, txChannel, response)
+}
+
+// fetchRpcTransaction loads a confirmed transaction over JSON-RPC and converts it.
+func fetchRpcTransaction(ctx context.Context, client *rpc.Client, sourceName string, signature string) (GeyserResponse, error) {
+	sig, err := solana.SignatureFromBase58(signature)
+	if err != nil {
+		return GeyserResponse{}, err
+	}
+
+	maxVersion := uint64(0)
+	result, err := client.GetTransaction(ctx, sig, &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     rpc.CommitmentConfirmed,
+		MaxSupportedTransactionVersion: &maxVersion,
+	})
+	if err != nil {
+		return GeyserResponse{}, err
+	}
+
+	if result.Transaction == nil {
+		return GeyserResponse{}, fmt.Errorf("transaction %s not found", signature)
+	}
+
+	tx, err := result.Transaction.GetTransaction()
+	if err != nil {
+		return GeyserResponse{}, err
+	}
+
+	return newRpcGeyserResponse(sourceName, result.Slot, tx, result.Meta), nil
+}
@@ -10,11 +10,36 @@ func CreateRoutes() *chi.Mux {
 	r.Use(middleware.Logger)
 
 	var TradeHandler = NewTradeHandler()
+	var LiquidityHandler = NewLiquidityHandler()
+	var StatsHandler = NewStatsHandler()
+	var PumpHandler = NewPumpHandler()
+	var TrackerHandler = NewTrackerHandler()
+	var SnapshotHandler = NewSnapshotHandler()
 
 	r.Route("/trade", func(r chi.Router) {
 		r.Get("/", TradeHandler.Get)
 		r.Delete("/", TradeHandler.DeleteAll)
 	})
 
+	r.Route("/liquidity", func(r chi.Router) {
+		r.Get("/", LiquidityHandler.Get)
+	})
+
+	r.Route("/snapshot", func(r chi.Router) {
+		r.Get("/", SnapshotHandler.Get)
+	})
+
+	r.Route("/pump", func(r chi.Router) {
+		r.Get("/", PumpHandler.Get)
+	})
+
+	r.Route("/tracker", func(r chi.Router) {
+		r.Get("/{ammId}", TrackerHandler.Get)
+	})
+
+	r.Route("/stats", func(r chi.Router) {
+		r.Get("/decode", StatsHandler.DecodeFailures)
+	})
+
 	return r
 }
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/storage"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/utils"
+)
+
+type snapshotHandler struct {
+}
+
+func NewSnapshotHandler() *snapshotHandler {
+	return &snapshotHandler{}
+}
+
+func (h *snapshotHandler) Get(w http.ResponseWriter, r *http.Request) {
+	decoded, err := utils.Decode[types.MySQLFilter](r)
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ctx := r.Context()
+	snapshots, err := storage.Snapshot.Search(decoded)
+
+	if err != nil {
+		select {
+		case <-ctx.Done():
+			http.Error(w, ErrTimeout, http.StatusGatewayTimeout)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	utils.Encode(w, r, http.StatusOK, snapshots)
+}
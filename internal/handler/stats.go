@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/coder"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/utils"
+)
+
+type statsHandler struct {
+}
+
+func NewStatsHandler() *statsHandler {
+	return &statsHandler{}
+}
+
+// DecodeFailures reports how many instructions failed to decode, per program and reason.
+func (h *statsHandler) DecodeFailures(w http.ResponseWriter, r *http.Request) {
+	utils.Encode(w, r, http.StatusOK, coder.DecodeFailures())
+}
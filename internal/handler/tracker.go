@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/go-chi/chi/v5"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/adapter"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/storage"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/utils"
+)
+
+type trackerHandler struct {
+}
+
+func NewTrackerHandler() *trackerHandler {
+	return &trackerHandler{}
+}
+
+// Get returns the tracking status of a pool with its LP and mint risk profiles.
+func (h *trackerHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ammId, err := solana.PublicKeyFromBase58(chi.URLParam(r, "ammId"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	redisClient, err := adapter.GetRedisClient(4)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	tracker, err := storage.GetTracked(redisClient, ammId.String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Pools seen before their LP moved have no profile yet
+	profile, _ := storage.GetLpProfile(redisClient, &ammId)
+	risk, _ := storage.GetMintRisk(redisClient, &ammId)
+
+	utils.Encode(w, r, http.StatusOK, types.TrackerStatus{
+		Tracker:   tracker,
+		LpProfile: profile,
+		MintRisk:  risk,
+	})
+}
@@ -0,0 +1,61 @@
+package bot
+
+import (
+	"log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/coder"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/generators"
+)
+
+func init() {
+	decoders.Register(config.RAYDIUM_CLMM, coder.NewRaydiumClmmCoder())
+	registerHandler(processClmmInstruction, coder.ClmmIncreaseLiquidity{}, coder.ClmmDecreaseLiquidity{}, coder.ClmmClosePosition{})
+}
+
+func processClmmInstruction(call *instructionCall, response generators.GeyserResponse) {
+	switch decoded := call.decoded.(type) {
+	case coder.ClmmIncreaseLiquidity:
+		poolId, positionId, err := getClmmLiquidityAccounts(coder.CLMM_INCREASE_POOL, coder.CLMM_INCREASE_POSITION, call.ins, response)
+		if err != nil {
+			return
+		}
+
+		log.Printf("ClmmIncreaseLiquidity | %s | %s | %s", response.MempoolTxns.Source, response.MempoolTxns.Signature, call.route)
+		setPositionPool(positionId, poolId)
+		processDeposit(call.program, poolId, response)
+	case coder.ClmmDecreaseLiquidity:
+		poolId, positionId, err := getClmmLiquidityAccounts(coder.CLMM_DECREASE_POOL, coder.CLMM_DECREASE_POSITION, call.ins, response)
+		if err != nil {
+			return
+		}
+
+		log.Printf("ClmmDecreaseLiquidity | %s | %s | %s", response.MempoolTxns.Source, response.MempoolTxns.Signature, call.route)
+		processPositionDecrease(call.program, poolId, positionId, decoded.Liquidity, response)
+	case coder.ClmmClosePosition:
+		positionId, err := getPublicKeyFromTx(coder.CLMM_CLOSE_POSITION, response.MempoolTxns, call.ins)
+		if err != nil {
+			return
+		}
+
+		log.Printf("ClmmClosePosition | %s | %s | %s", response.MempoolTxns.Source, response.MempoolTxns.Signature, call.route)
+		processPositionClose(call.program, positionId, response)
+	}
+}
+
+// getClmmLiquidityAccounts returns the pool and position of a liquidity instruction
+// from their positions, which differ between increase and decrease.
+func getClmmLiquidityAccounts(poolPos int, positionPos int, ins generators.TxInstruction, tx generators.GeyserResponse) (*solana.PublicKey, *solana.PublicKey, error) {
+	poolId, err := getPublicKeyFromTx(poolPos, tx.MempoolTxns, ins)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	positionId, err := getPublicKeyFromTx(positionPos, tx.MempoolTxns, ins)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return poolId, positionId, nil
+}
@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"reflect"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/coder"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/generators"
+)
+
+// instructionHandler handles one decoded instruction found in a transaction.
+type instructionHandler func(call *instructionCall, response generators.GeyserResponse)
+
+var (
+	decoders = coder.NewRegistry()
+	handlers = make(map[reflect.Type][]instructionHandler)
+)
+
+// instructionCall is an instruction found in a transaction, either at the top level
+// or invoked by another program through CPI. caller is the program that invoked a
+// CPI, found from the stack height where the source reports it and otherwise the
+// program that most likely did.
+type instructionCall struct {
+	program      solana.PublicKey
+	ins          generators.TxInstruction
+	decoded      interface{}
+	route        string
+	outerProgram string
+	caller       solana.PublicKey
+	txn          *txnDetails
+}
+
+// txnDetails collects the compute budget, tips and swaps of a transaction while its
+// instructions are dispatched. Swaps are recorded once all of it is known.
+type txnDetails struct {
+	computeLimit uint32
+	computePrice uint64
+	tip          string
+	tipAmount    int64
+	swaps        []instructionCall
+}
+
+// registerHandler calls handler for every decoded instruction of the types of events.
+// Handlers of the same type run in the order they were registered.
+func registerHandler(handler instructionHandler, events ...interface{}) {
+	for _, event := range events {
+		eventType := reflect.TypeOf(event)
+		handlers[eventType] = append(handlers[eventType], handler)
+	}
+}
+
+// dispatchInstruction decodes the instruction with the decoder of its program and
+// passes it to the handlers of its type. Programs without a decoder are skipped.
+func dispatchInstruction(call *instructionCall, response generators.GeyserResponse) {
+	if !decoders.Has(call.program) {
+		return
+	}
+
+	decoded, err := decoders.Decode(call.program, call.ins.Data)
+	if err != nil {
+		return
+	}
+
+	call.decoded = decoded
+
+	for _, handler := range handlers[reflect.TypeOf(decoded)] {
+		handler(call, response)
+	}
+}
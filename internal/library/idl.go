@@ -0,0 +1,68 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/coder"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/generators"
+)
+
+func init() {
+	registerHandler(processAnchorInstruction, coder.AnchorInstruction{})
+}
+
+// RegisterIdls registers a decoder for every Anchor program configured with an IDL.
+// Programs that already have a built-in decoder are refused, since their handlers
+// expect the built-in instruction types.
+func RegisterIdls(idls []config.IdlConfig) error {
+	for _, cfg := range idls {
+		programId, err := solana.PublicKeyFromBase58(cfg.Program)
+		if err != nil {
+			return err
+		}
+
+		if decoders.Has(programId) {
+			return fmt.Errorf("%s already has a built-in decoder", programId)
+		}
+
+		idl, err := coder.LoadAnchorIdl(cfg.Path)
+		if err != nil {
+			return err
+		}
+
+		if address := idl.ProgramAddress(); address != "" && address != cfg.Program {
+			log.Printf("IDL %s is for program %s, decoding %s with it", cfg.Path, address, programId)
+		}
+
+		idlCoder, err := coder.NewAnchorIdlCoder(idl)
+		if err != nil {
+			return fmt.Errorf("%s: %w", cfg.Path, err)
+		}
+
+		decoders.Register(programId, idlCoder)
+		log.Printf("Decoding %s (%s) from %s", idl.ProgramName(), programId, cfg.Path)
+	}
+
+	return nil
+}
+
+// processAnchorInstruction logs an instruction decoded from an IDL with its
+// arguments and named accounts.
+func processAnchorInstruction(call *instructionCall, response generators.GeyserResponse) {
+	decoded := call.decoded.(coder.AnchorInstruction)
+
+	accounts := make([]string, 0, len(decoded.Accounts))
+	for i, name := range decoded.Accounts {
+		account, err := getPublicKeyFromTx(i, response.MempoolTxns, call.ins)
+		if err != nil {
+			break
+		}
+		accounts = append(accounts, fmt.Sprintf("%s=%s", name, account))
+	}
+
+	log.Printf("%s.%s | %s | %s | %s | %v | %s", decoded.Program, decoded.Name, response.MempoolTxns.Source, response.MempoolTxns.Signature, call.route, decoded.Args, strings.Join(accounts, " "))
+}
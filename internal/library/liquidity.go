@@ -0,0 +1,31 @@
+package bot
+
+import (
+	"log"
+	"time"
+
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/adapter"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/storage"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+)
+
+func SetLiquidityEvent(event *types.LiquidityEvent) error {
+	db, err := adapter.GetMySQLClient()
+	if err != nil {
+		log.Printf("Failed to get initialize mysql instance: %v", err)
+		return err
+	}
+
+	dbMutex.Lock()
+	defer dbMutex.Unlock()
+
+	liquidityStorage := storage.NewLiquidityStorage(db)
+
+	event.Timestamp = time.Now().Unix()
+	err = liquidityStorage.Set(event)
+	if err != nil {
+		log.Printf("Failed to set liquidity event: %v", err)
+	}
+
+	return nil
+}
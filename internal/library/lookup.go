@@ -61,6 +61,8 @@ func SetLookupTable(ammId solana.PublicKey, lookup *addresslookuptable.AddressLo
 func GenerateTableLookup(addressTableLookups []generators.TxAddressTableLookup) []LookupIndex {
 	var lookupIndexes []LookupIndex
 
+	// Loaded addresses follow the static keys as every table's writable indexes,
+	// then every table's readonly indexes
 	for _, lookup := range addressTableLookups {
 		for _, index := range lookup.WritableIndexes {
 			lookupIndexes = append(lookupIndexes, LookupIndex{
@@ -68,6 +70,9 @@ func GenerateTableLookup(addressTableLookups []generators.TxAddressTableLookup)
 				LookupTableKey:   lookup.AccountKey,
 			})
 		}
+	}
+
+	for _, lookup := range addressTableLookups {
 		for _, index := range lookup.ReadonlyIndexes {
 			lookupIndexes = append(lookupIndexes, LookupIndex{
 				LookupTableIndex: index,
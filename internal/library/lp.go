@@ -0,0 +1,273 @@
+package bot
+
+import (
+	"errors"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/adapter"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/coder"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/generators"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/liquidity"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/rpc"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/storage"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+)
+
+// LP_WATCH_WINDOW is how long the LP mint of a new pool is streamed. Creators burn or
+// lock their LP tokens soon after the pool opens, if they do at all.
+const LP_WATCH_WINDOW = 24 * time.Hour
+
+// Profiles are read, changed and written back, so updates to them are serialised
+var lpProfileMutex sync.Mutex
+
+func init() {
+	registerHandler(processLpTokenInstruction, coder.TokenBurn{}, coder.TokenBurnChecked{}, coder.TokenTransfer{}, coder.TokenTransferChecked{})
+}
+
+// recordLpCreator starts the LP profile of a new pool with the LP tokens its creator,
+// the fee payer of the initialize transaction, received.
+func recordLpCreator(programId solana.PublicKey, ammId *solana.PublicKey, tx generators.GeyserResponse) {
+	if tx.MempoolTxns.Error != "" || len(tx.MempoolTxns.AccountKeys) == 0 {
+		return
+	}
+
+	pKey, err := liquidity.GetPoolKeysByProgram(programId, ammId)
+	if err != nil {
+		log.Printf("%s | %s", ammId, err)
+		return
+	}
+
+	creator := tx.MempoolTxns.AccountKeys[0]
+
+	lpProfileMutex.Lock()
+	defer lpProfileMutex.Unlock()
+
+	profile := &types.LpProfile{
+		AmmId:       ammId,
+		LpMint:      &pKey.LpMint,
+		Creator:     creator,
+		CreatorHeld: GetOwnerBalance(tx.MempoolTxns.PostTokenBalances, pKey.LpMint, creator).Uint64(),
+	}
+
+	saveLpProfile(programId, profile)
+
+	// LP tokens are burned and locked through the token programs, mostly in
+	// transactions that don't call the pool program
+	generators.Watched.Add(pKey.LpMint.String(), LP_WATCH_WINDOW)
+}
+
+// processLpTokenInstruction follows LP tokens that are burned, sent to the incinerator
+// or moved into an account of a known locker. Tokens of other mints are ignored.
+func processLpTokenInstruction(call *instructionCall, response generators.GeyserResponse) {
+	if response.MempoolTxns.Error != "" {
+		return
+	}
+
+	var (
+		amount         uint64
+		mintPos        = -1
+		sourcePos      int
+		destinationPos = -1
+	)
+
+	switch decoded := call.decoded.(type) {
+	case coder.TokenBurn:
+		amount, mintPos, sourcePos = decoded.Amount, coder.TOKEN_BURN_MINT, coder.TOKEN_BURN_ACCOUNT
+	case coder.TokenBurnChecked:
+		amount, mintPos, sourcePos = decoded.Amount, coder.TOKEN_BURN_MINT, coder.TOKEN_BURN_ACCOUNT
+	case coder.TokenTransfer:
+		amount, sourcePos, destinationPos = decoded.Amount, coder.TOKEN_TRANSFER_SOURCE, coder.TOKEN_TRANSFER_DESTINATION
+	case coder.TokenTransferChecked:
+		amount, mintPos, sourcePos, destinationPos = decoded.Amount, coder.TOKEN_TRANSFER_CHECKED_MINT, coder.TOKEN_TRANSFER_CHECKED_SOURCE, coder.TOKEN_TRANSFER_CHECKED_DESTINATION
+	default:
+		return
+	}
+
+	if amount == 0 {
+		return
+	}
+
+	lpMint, err := getInstructionMint(call.ins, mintPos, sourcePos, response)
+	if err != nil {
+		return
+	}
+
+	redisClient, err := adapter.GetRedisClient(4)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	ammId, err := storage.GetLpMintPool(redisClient, lpMint)
+	if err != nil {
+		return
+	}
+
+	pKey, err := storage.GetPoolKeys(redisClient, ammId)
+	if err != nil {
+		log.Printf("%s | %s", ammId, err)
+		return
+	}
+
+	var burned, locked bool
+	if destinationPos == -1 {
+		// Withdrawals burn the LP tokens they redeem through the pool program. Burns
+		// of any other program, including the pool creator's, remove the LP for good.
+		burned = call.route == ROUTE_DIRECT || call.caller != pKey.ProgramID
+	} else {
+		// Tokens leaving a locker are unlocked, not locked again
+		if source, ok := getTokenAccountAt(call.ins, sourcePos, response.MempoolTxns.PreTokenBalances); ok && isLockerOwner(source.Owner, call.caller) {
+			return
+		}
+
+		destination, ok := getTokenAccountAt(call.ins, destinationPos, response.MempoolTxns.PostTokenBalances)
+		switch {
+		case ok && destination.Owner == config.INCINERATOR.String():
+			burned = true
+		case ok && isLockerOwner(destination.Owner, call.caller):
+			locked = true
+		}
+	}
+
+	lpProfileMutex.Lock()
+	defer lpProfileMutex.Unlock()
+
+	profile, err := storage.GetLpProfile(redisClient, ammId)
+	if err != nil {
+		profile = &types.LpProfile{AmmId: ammId, LpMint: lpMint}
+	}
+
+	switch {
+	case burned:
+		profile.Burned += amount
+		log.Printf("%s | LP burned %d | %s", ammId, amount, response.MempoolTxns.Signature)
+	case locked:
+		profile.Locked += amount
+		log.Printf("%s | LP locked %d | %s", ammId, amount, response.MempoolTxns.Signature)
+	}
+
+	if profile.Creator != "" {
+		if held, ok := LookupOwnerBalance(response.MempoolTxns.PostTokenBalances, *lpMint, profile.Creator); ok {
+			profile.CreatorHeld = held.Uint64()
+		}
+	}
+
+	saveLpProfile(pKey.ProgramID, profile)
+}
+
+// saveLpProfile refreshes the LP supply and shares of the profile and stores it. The
+// shares of the last known supply are kept when the pool can't be read.
+func saveLpProfile(programId solana.PublicKey, profile *types.LpProfile) {
+	redisClient, err := adapter.GetRedisClient(4)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	supply, _, err := getLpSupply(programId, profile.AmmId)
+	if err != nil {
+		log.Printf("%s | %s", profile.AmmId, err)
+	} else {
+		profile.Supply = supply
+	}
+
+	if profile.Supply > 0 {
+		profile.BurnedPct = lpShare(profile.Burned, profile.Supply)
+		profile.LockedPct = lpShare(profile.Locked, profile.Supply)
+		profile.CreatorPct = lpShare(profile.CreatorHeld, profile.Supply)
+	}
+
+	profile.LastUpdated = time.Now().Unix()
+
+	if err := storage.SetLpProfile(redisClient, profile); err != nil {
+		log.Print(err)
+	}
+}
+
+// getLpSupply returns the LP supply the pool keeps itself, which only changes on
+// deposits and withdrawals, and the slot it was read at.
+func getLpSupply(programId solana.PublicKey, ammId *solana.PublicKey) (uint64, uint64, error) {
+	accounts, slot, err := rpc.GetMultipleAccountsData([]solana.PublicKey{*ammId})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch programId {
+	case config.RAYDIUM_AMM_V4:
+		state, err := coder.NewRaydiumLiquidityCoder().RaydiumLiquidityDecode(accounts[0])
+		if err != nil {
+			return 0, 0, err
+		}
+		return state.LpReserve, slot, nil
+	case config.RAYDIUM_CPMM:
+		state, err := coder.NewRaydiumCpmmCoder().DecodePoolState(accounts[0])
+		if err != nil {
+			return 0, 0, err
+		}
+		return state.LpSupply, slot, nil
+	default:
+		return 0, 0, errors.New("LP supply unknown for pool program")
+	}
+}
+
+func lpShare(amount uint64, supply uint64) float64 {
+	return min(float64(amount)/float64(supply), 1) * 100
+}
+
+// getInstructionMint returns the mint named by the instruction, or the mint of its
+// source token account for instructions that don't name one.
+func getInstructionMint(ins generators.TxInstruction, mintPos int, sourcePos int, tx generators.GeyserResponse) (*solana.PublicKey, error) {
+	if mintPos >= 0 {
+		return getPublicKeyFromTx(mintPos, tx.MempoolTxns, ins)
+	}
+
+	source, ok := getTokenAccountAt(ins, sourcePos, tx.MempoolTxns.PreTokenBalances)
+	if !ok {
+		return nil, errors.New("source token account not in transaction balances")
+	}
+
+	mint, err := solana.PublicKeyFromBase58(source.Mint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mint, nil
+}
+
+func getTokenAccountAt(ins generators.TxInstruction, pos int, tokenBalances []types.TxTokenBalance) (types.TxTokenBalance, bool) {
+	if pos >= len(ins.Accounts) {
+		return types.TxTokenBalance{}, false
+	}
+	return GetTokenAccount(tokenBalances, uint32(ins.Accounts[pos]))
+}
+
+// isLockerOwner reports whether a token account owner is a locker program or one of
+// its addresses. Lockers keep tokens in accounts owned by a program derived address,
+// which is either an account of the locker or, without any data, only known to be
+// the locker's when the locker itself moves the tokens.
+func isLockerOwner(owner string, caller solana.PublicKey) bool {
+	ownerKey, err := solana.PublicKeyFromBase58(owner)
+	if err != nil {
+		return false
+	}
+
+	if slices.Contains(config.LP_LOCKERS, ownerKey) {
+		return true
+	}
+
+	// Wallets are on the curve, program derived addresses never are
+	if ownerKey.IsOnCurve() {
+		return false
+	}
+
+	if program, err := rpc.GetAccountOwner(ownerKey); err == nil && slices.Contains(config.LP_LOCKERS, program) {
+		return true
+	}
+
+	return slices.Contains(config.LP_LOCKERS, caller)
+}
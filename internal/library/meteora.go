@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"log"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/coder"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/generators"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/liquidity"
+)
+
+func init() {
+	decoders.Register(config.METEORA_DLMM, coder.NewMeteoraDlmmCoder())
+	decoders.Register(config.METEORA_DAMM, coder.NewMeteoraDammCoder())
+	registerHandler(processMeteoraInstruction,
+		coder.DlmmRemoveLiquidity{}, coder.DlmmRemoveAllLiquidity{}, coder.DlmmRemoveLiquidityByRange{},
+		coder.DlmmClosePosition{}, coder.DammRemoveBalanceLiquidity{})
+}
+
+func processMeteoraInstruction(call *instructionCall, response generators.GeyserResponse) {
+	var name string
+	var poolPos int
+
+	switch call.decoded.(type) {
+	case coder.DlmmRemoveLiquidity:
+		name, poolPos = "DlmmRemoveLiquidity", coder.DLMM_REMOVE_LB_PAIR
+	case coder.DlmmRemoveAllLiquidity:
+		name, poolPos = "DlmmRemoveAllLiquidity", coder.DLMM_REMOVE_LB_PAIR
+	case coder.DlmmRemoveLiquidityByRange:
+		name, poolPos = "DlmmRemoveLiquidityByRange", coder.DLMM_REMOVE_LB_PAIR
+	case coder.DlmmClosePosition:
+		name, poolPos = "DlmmClosePosition", coder.DLMM_CLOSE_LB_PAIR
+	case coder.DammRemoveBalanceLiquidity:
+		name, poolPos = "DammRemoveBalanceLiquidity", coder.DAMM_REMOVE_POOL
+	default:
+		return
+	}
+
+	log.Printf("%s | %s | %s | %s", name, response.MempoolTxns.Source, response.MempoolTxns.Signature, call.route)
+
+	poolId, err := getPublicKeyFromTx(poolPos, response.MempoolTxns, call.ins)
+	if err != nil {
+		log.Print("Unable to retrieve AMM ID")
+		return
+	}
+
+	switch call.decoded.(type) {
+	case coder.DlmmRemoveLiquidity, coder.DlmmRemoveAllLiquidity, coder.DlmmRemoveLiquidityByRange:
+		processWithdraw(call.program, poolId, 0, response)
+	case coder.DlmmClosePosition:
+		// The position was emptied by an earlier removal, so only the reserve is checked
+		pKey, err := liquidity.GetDlmmPoolKeys(poolId)
+		if err != nil {
+			log.Printf("%s | %s", poolId, err)
+			return
+		}
+		checkPoolDrained(call.program, poolId, pKey, response)
+	case coder.DammRemoveBalanceLiquidity:
+		processDammRemove(call.ins, poolId, response)
+	}
+}
+
+// processDammRemove measures the removal by the pool's share of its vaults, from
+// the pool's vault LP balances. Dynamic AMM vaults are shared between pools, so
+// their token balance says nothing about a single pool, and what the pool has left
+// is valued at the tokens each vault LP it burned returned.
+func processDammRemove(ins generators.TxInstruction, poolId *solana.PublicKey, tx generators.GeyserResponse) {
+	pKey, err := liquidity.GetDammPoolKeys(poolId)
+	if err != nil {
+		log.Printf("%s | %s", poolId, err)
+		return
+	}
+
+	var measure withdrawMeasure
+
+	if vaultLpMint, err := getPublicKeyFromTx(coder.DAMM_REMOVE_A_VAULT_LP_MINT, tx.MempoolTxns, ins); err == nil {
+		if removed, ok := GetRemovedShare(tx.MempoolTxns.PreTokenBalances, tx.MempoolTxns.PostTokenBalances, *vaultLpMint, poolId.String()); ok {
+			measure.RemovedPct = removed * 100
+		}
+	}
+
+	if quote, swap, err := liquidity.GetQuote(pKey); err != nil {
+		log.Printf("%s | %s", poolId, err)
+	} else {
+		vaultLpMintPos, tokenVault := coder.DAMM_REMOVE_B_VAULT_LP_MINT, pKey.QuoteVault
+		if swap {
+			vaultLpMintPos, tokenVault = coder.DAMM_REMOVE_A_VAULT_LP_MINT, pKey.BaseVault
+		}
+
+		if vaultLpMint, err := getPublicKeyFromTx(vaultLpMintPos, tx.MempoolTxns, ins); err == nil {
+			if reserve, ok := getDammReserve(tx.MempoolTxns, *vaultLpMint, tokenVault, poolId); ok {
+				measure.Quote, measure.Reserve = &quote, liquidity.QuoteValue(reserve, quote)
+			}
+		}
+	}
+
+	ruleWithdraw(config.METEORA_DAMM, poolId, pKey, tx, measure)
+}
+
+// getDammReserve returns the tokens the pool's vault LP of one side is worth after
+// the transaction, at the rate the vault LP it burned was paid out. It reports false
+// when the pool burned none.
+func getDammReserve(tx generators.MempoolTxn, vaultLpMint solana.PublicKey, tokenVault solana.PublicKey, poolId *solana.PublicKey) (*big.Int, bool) {
+	post := GetOwnerBalance(tx.PostTokenBalances, vaultLpMint, poolId.String())
+	burned := new(big.Int).Sub(GetOwnerBalance(tx.PreTokenBalances, vaultLpMint, poolId.String()), post)
+
+	withdrawn := GetVaultBalanceChange(tx, tokenVault)
+	if burned.Sign() <= 0 || withdrawn.Sign() <= 0 {
+		return nil, false
+	}
+
+	reserve := new(big.Int).Mul(post, withdrawn)
+	return reserve.Quo(reserve, burned), true
+}
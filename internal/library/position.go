@@ -0,0 +1,203 @@
+package bot
+
+import (
+	"errors"
+	"log"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/adapter"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/coder"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/generators"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/liquidity"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/rpc"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/storage"
+)
+
+// Raydium CLMM and Orca Whirlpool hold liquidity in positions rather than LP tokens.
+// A decrease is classified by the share of the pool's active liquidity it takes.
+// Both programs only close empty positions, so closing one removes nothing that the
+// decrease emptying it, usually earlier in the same transaction, did not measure.
+
+// processPositionDecrease measures the decrease by the share of the pool's active
+// liquidity it takes, and values the pool from its quote vault after the transaction.
+func processPositionDecrease(programId solana.PublicKey, poolId *solana.PublicKey, positionId *solana.PublicKey, decreased coder.Uint128, tx generators.GeyserResponse) {
+	setPositionPool(positionId, poolId)
+
+	if tx.MempoolTxns.Error != "" {
+		return
+	}
+
+	pKey, err := liquidity.GetPoolKeysByProgram(programId, poolId)
+	if err != nil {
+		log.Printf("%s | %s", poolId, err)
+		return
+	}
+
+	var measure withdrawMeasure
+
+	if share, err := getActiveLiquidityShare(programId, poolId, positionId, decreased, tx.MempoolTxns.Slot); err != nil {
+		log.Printf("%s | %s", poolId, err)
+	} else {
+		measure.RemovedPct = share * 100
+	}
+
+	if quote, _, err := liquidity.GetQuote(pKey); err != nil {
+		log.Printf("%s | %s", poolId, err)
+	} else if reserve, ok := LookupOwnerBalance(tx.MempoolTxns.PostTokenBalances, quote.Mint, poolId.String()); ok {
+		// The vaults of both programs are owned by the pool account
+		measure.Quote, measure.Reserve = &quote, liquidity.QuoteValue(reserve, quote)
+	}
+
+	ruleWithdraw(programId, poolId, pKey, tx, measure)
+}
+
+// processPositionClose logs the pool of a closed position.
+func processPositionClose(programId solana.PublicKey, positionId *solana.PublicKey, tx generators.GeyserResponse) {
+	if tx.MempoolTxns.Error != "" {
+		return
+	}
+
+	poolId, err := getPositionPool(programId, positionId)
+	if err != nil {
+		log.Printf("%s | Unable to find pool of closed position: %s", positionId, err)
+		return
+	}
+
+	log.Printf("%s | Position %s closed | %s", poolId, positionId, tx.MempoolTxns.Signature)
+}
+
+// positionLiquidity is a pool's active liquidity and whether a position is in range
+// of the pool's current tick, read at Slot.
+type positionLiquidity struct {
+	Active  coder.Uint128
+	InRange bool
+	Slot    uint64
+}
+
+// getActiveLiquidityShare returns the share of the pool's active liquidity before
+// the transaction at slot that the decrease takes. Only a position in range of the
+// current tick adds to the active liquidity. Accounts are read at confirmed, which
+// is usually before the processed transaction, and a read that already includes it
+// has the decrease added back.
+func getActiveLiquidityShare(programId solana.PublicKey, poolId *solana.PublicKey, positionId *solana.PublicKey, decreased coder.Uint128, slot uint64) (float64, error) {
+	position, err := getPositionLiquidity(programId, poolId, positionId)
+	if err != nil {
+		return 0, err
+	}
+
+	if !position.InRange || decreased.IsZero() {
+		return 0, nil
+	}
+
+	active := position.Active.BigInt()
+	if position.Slot >= slot {
+		active.Add(active, decreased.BigInt())
+	}
+
+	share, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(decreased.BigInt()),
+		new(big.Float).SetInt(active),
+	).Float64()
+
+	return min(share, 1), nil
+}
+
+// getPositionLiquidity reads the pool and position at one slot. A position emptied
+// and closed in the same transaction is gone once the read includes it, and is then
+// taken to have been in range.
+func getPositionLiquidity(programId solana.PublicKey, poolId *solana.PublicKey, positionId *solana.PublicKey) (*positionLiquidity, error) {
+	accounts, slot, err := rpc.GetMultipleAccountsData([]solana.PublicKey{*poolId, *positionId})
+	if err != nil {
+		accounts, slot, err = rpc.GetMultipleAccountsData([]solana.PublicKey{*poolId})
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, nil)
+	}
+
+	result := &positionLiquidity{InRange: true, Slot: slot}
+
+	switch programId {
+	case config.RAYDIUM_CLMM:
+		clmmCoder := coder.NewRaydiumClmmCoder()
+
+		pool, err := clmmCoder.DecodePoolState(accounts[0])
+		if err != nil {
+			return nil, err
+		}
+		result.Active = pool.Liquidity
+
+		if accounts[1] != nil {
+			position, err := clmmCoder.DecodePersonalPosition(accounts[1])
+			if err != nil {
+				return nil, err
+			}
+			result.InRange = position.TickLowerIndex <= pool.TickCurrent && pool.TickCurrent < position.TickUpperIndex
+		}
+	case config.ORCA_WHIRLPOOL:
+		whirlpoolCoder := coder.NewOrcaWhirlpoolCoder()
+
+		pool, err := whirlpoolCoder.DecodeWhirlpool(accounts[0])
+		if err != nil {
+			return nil, err
+		}
+		result.Active = pool.Liquidity
+
+		if accounts[1] != nil {
+			position, err := whirlpoolCoder.DecodePosition(accounts[1])
+			if err != nil {
+				return nil, err
+			}
+			result.InRange = position.TickLowerIndex <= pool.TickCurrentIndex && pool.TickCurrentIndex < position.TickUpperIndex
+		}
+	default:
+		return nil, errors.New("not a concentrated liquidity program")
+	}
+
+	return result, nil
+}
+
+func setPositionPool(positionId *solana.PublicKey, poolId *solana.PublicKey) {
+	redisClient, err := adapter.GetRedisClient(4)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	if err := storage.SetPositionPool(redisClient, positionId, poolId); err != nil {
+		log.Print(err)
+	}
+}
+
+// getPositionPool looks up the pool of a position, falling back to the position
+// account when the position was never seen in a liquidity instruction.
+func getPositionPool(programId solana.PublicKey, positionId *solana.PublicKey) (*solana.PublicKey, error) {
+	redisClient, err := adapter.GetRedisClient(4)
+	if err != nil {
+		return nil, err
+	}
+
+	poolId, err := storage.GetPositionPool(redisClient, positionId)
+	if err == nil {
+		return poolId, nil
+	}
+
+	switch programId {
+	case config.RAYDIUM_CLMM:
+		position, err := rpc.GetClmmPersonalPosition(positionId)
+		if err != nil {
+			return nil, err
+		}
+		return &position.PoolId, nil
+	case config.ORCA_WHIRLPOOL:
+		position, err := rpc.GetWhirlpoolPosition(positionId)
+		if err != nil {
+			return nil, err
+		}
+		return &position.Whirlpool, nil
+	default:
+		return nil, errors.New("not a concentrated liquidity program")
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"log"
 	"math/big"
 	"slices"
-	"strings"
 	"time"
 
 	"github.com/gagliardetto/solana-go"
@@ -24,108 +23,236 @@ var (
 	latestBlockhash string
 )
 
+const (
+	ROUTE_DIRECT = "direct"
+	ROUTE_CPI    = "cpi"
+)
+
+const (
+	SWAP_BASE_IN  = "SwapBaseIn"
+	SWAP_BASE_OUT = "SwapBaseOut"
+)
+
+func init() {
+	decoders.Register(config.COMPUTE_PROGRAM, coder.NewComputeBudgetCoder())
+	decoders.Register(config.TRANSFER_PROGRAM, coder.NewSystemCoder())
+	decoders.Register(config.RAYDIUM_AMM_V4, coder.NewRaydiumAmmInstructionCoder())
+	decoders.Register(config.RAYDIUM_CPMM, coder.NewRaydiumCpmmCoder())
+
+	registerHandler(processComputeBudget, coder.SetComputeUnitLimit{}, coder.SetComputeUnitPrice{})
+	registerHandler(processTipTransfer, coder.SystemTransfer{}, coder.SystemTransferWithSeed{}, coder.SystemWithdrawNonceAccount{})
+	registerHandler(processPoolInstruction,
+		coder.Initialize2{}, coder.CpmmInitialize{},
+		coder.Deposit{}, coder.CpmmDeposit{},
+		coder.Withdraw{}, coder.CpmmWithdraw{})
+	registerHandler(queueSwap, coder.SwapBaseIn{}, coder.SwapBaseOut{}, coder.CpmmSwapBaseInput{}, coder.CpmmSwapBaseOutput{})
+}
+
 func ProcessResponse(response generators.GeyserResponse) {
 	latestBlockhash = response.MempoolTxns.RecentBlockhash
 
 	var (
-		isProcess    bool
-		ix           generators.TxInstruction
-		res          generators.GeyserResponse
-		computeLimit uint32
-		computePrice uint32
-		tipAmount    int64
-		tip          string
+		txn          txnDetails
+		instructions int
+		builtins     int
 		status       = "success"
 	)
 
-	c := coder.NewRaydiumAmmInstructionCoder()
 	for _, ins := range response.MempoolTxns.Instructions {
-		programId := response.MempoolTxns.AccountKeys[ins.ProgramIdIndex]
+		programId, err := getAccountKey(int(ins.ProgramIdIndex), response.MempoolTxns)
+		if err != nil {
+			continue
+		}
 
-		if programId == config.RAYDIUM_AMM_V4.String() {
-			decodedIx, err := c.Decode(ins.Data)
-			if err != nil {
-				continue
-			}
+		if coder.IsBuiltinProgram(*programId) {
+			builtins++
+		} else {
+			instructions++
+		}
 
-			switch decodedIx.(type) {
-			case coder.Initialize2:
-				log.Printf("Initialize2 | %s | %s", response.MempoolTxns.Source, response.MempoolTxns.Signature)
-				processInitialize2(ins, response)
-			case coder.Withdraw:
-				log.Printf("Withdraw | %s | %s", response.MempoolTxns.Source, response.MempoolTxns.Signature)
-				processWithdraw(ins, response)
-			case coder.SwapBaseIn:
-				isProcess = true
-				ix = ins
-				res = response
-			case coder.SwapBaseOut:
-			default:
-				log.Println("Unknown instruction type")
-			}
+		call := instructionCall{program: *programId, ins: ins, route: ROUTE_DIRECT, outerProgram: programId.String(), txn: &txn}
+		dispatchInstruction(&call, response)
+	}
+
+	// Instructions routed through aggregators or bot programs only show up as inner
+	// instructions of the outer program
+	for _, inner := range response.MempoolTxns.InnerInstructions {
+		if int(inner.Index) >= len(response.MempoolTxns.Instructions) {
+			continue
+		}
+
+		outer := response.MempoolTxns.Instructions[inner.Index]
+		outerProgram, err := getAccountKey(int(outer.ProgramIdIndex), response.MempoolTxns)
+		if err != nil {
+			continue
 		}
 
-		if programId == config.COMPUTE_PROGRAM.String() {
-			computeDecoded, err := c.DecodeCompute(ins.Data)
+		stack := newInvocationStack(*outerProgram)
+		for _, ins := range inner.Instructions {
+			programId, err := getAccountKey(int(ins.ProgramIdIndex), response.MempoolTxns)
 			if err != nil {
 				continue
 			}
 
-			if computeDecoded.Instruction == 2 {
-				computeLimit = computeDecoded.Value
-			}
-
-			if computeDecoded.Instruction == 3 {
-				computePrice = computeDecoded.Value
-			}
+			caller := stack.invoke(*programId, ins.StackHeight)
+			call := instructionCall{program: *programId, ins: ins, route: ROUTE_CPI, outerProgram: outerProgram.String(), caller: caller, txn: &txn}
+			dispatchInstruction(&call, response)
 		}
+	}
 
-		if programId == config.TRANSFER_PROGRAM.String() {
-			transfer, err := c.DecodeTransfer(ins.Data)
+	if response.MempoolTxns.Error != "" {
+		status = "failed"
+	}
 
-			if err != nil {
-				continue
-			}
+	// Without an explicit limit the runtime charges the price on its default limit
+	chargedLimit := txn.computeLimit
+	if chargedLimit == 0 {
+		chargedLimit = coder.DefaultComputeUnitLimit(instructions, builtins)
+	}
 
-			var accounts []string
+	priorityFee := coder.PriorityFee(uint64(chargedLimit), txn.computePrice)
 
-			for _, idx := range ins.Accounts {
-				accountKeysLength := len(response.MempoolTxns.AccountKeys)
+	for _, swap := range txn.swaps {
+		processSwap(swap, response, txn.computeLimit, txn.computePrice, priorityFee, txn.tip, txn.tipAmount, status)
+	}
+}
 
-				if idx >= uint8(accountKeysLength) {
-					accounts = append(accounts, response.MempoolTxns.AccountKeys[accountKeysLength-1])
-				} else {
-					accounts = append(accounts, response.MempoolTxns.AccountKeys[idx])
+// invocationStack follows the programs executing the inner instructions of one
+// top-level instruction, to find the program that invoked each of them.
+type invocationStack struct {
+	// programs[i] is the program last executing at stack height i+1
+	programs []solana.PublicKey
+	last     solana.PublicKey
+}
 
-				}
-			}
+func newInvocationStack(outerProgram solana.PublicKey) *invocationStack {
+	return &invocationStack{programs: []solana.PublicKey{outerProgram}, last: outerProgram}
+}
 
-			destination := accounts[1]
+// invoke returns the program that invoked programId at height. Without a height the
+// caller is the last program that invokes others, since token programs and builtins
+// only invoke each other and the instructions after them were still invoked by it.
+func (stack *invocationStack) invoke(programId solana.PublicKey, height uint32) solana.PublicKey {
+	caller := stack.last
+	if !isLeafProgram(programId) {
+		stack.last = programId
+	}
 
-			if destination == "" {
-				continue
-			}
+	if height < 2 || int(height) > len(stack.programs)+1 {
+		return caller
+	}
 
-			isJitoTipAccount := slices.Contains(JitoTipAccounts, destination)
-			isBloxRouteTipAccount := strings.EqualFold(destination, config.BLOXROUTE_TIP.String())
+	stack.programs = append(stack.programs[:height-1], programId)
+	return stack.programs[height-2]
+}
 
-			if isJitoTipAccount {
-				tip = tipAccount[0]
-				tipAmount = transfer.Amount
-			} else if isBloxRouteTipAccount {
-				tip = tipAccount[1]
-				tipAmount = transfer.Amount
-			}
-		}
+// isLeafProgram reports whether programId never invokes programs outside the token
+// programs and builtins.
+func isLeafProgram(programId solana.PublicKey) bool {
+	switch programId {
+	case config.TOKEN_PROGRAM_ID, config.TOKEN_2022_PROGRAM_ID, config.ASSOCIATED_TOKEN_PROGRAM_ID:
+		return true
+	}
+	return coder.IsBuiltinProgram(programId)
+}
 
+// processComputeBudget keeps the compute unit limit and price of the transaction.
+// The runtime only applies compute budget instructions at the top level.
+func processComputeBudget(call *instructionCall, response generators.GeyserResponse) {
+	if call.route != ROUTE_DIRECT {
+		return
 	}
 
-	if response.MempoolTxns.Error != "" {
-		status = "failed"
+	switch decoded := call.decoded.(type) {
+	case coder.SetComputeUnitLimit:
+		call.txn.computeLimit = decoded.Units
+	case coder.SetComputeUnitPrice:
+		call.txn.computePrice = decoded.MicroLamports
 	}
+}
 
-	if isProcess {
-		processSwapBaseIn(ix, res, computeLimit, computePrice, tip, tipAmount, status)
+// processTipTransfer adds top level transfers to a Jito or bloXroute tip account to
+// the tip of the transaction.
+func processTipTransfer(call *instructionCall, response generators.GeyserResponse) {
+	if call.route != ROUTE_DIRECT {
+		return
+	}
+
+	lamports, destinationPos, ok := coder.LamportsTransferred(call.decoded)
+	if !ok {
+		return
+	}
+
+	destination, err := getPublicKeyFromTx(destinationPos, response.MempoolTxns, call.ins)
+	if err != nil {
+		return
+	}
+
+	isJitoTipAccount := slices.Contains(JitoTipAccounts, destination.String())
+	isBloxRouteTipAccount := *destination == config.BLOXROUTE_TIP
+
+	if isJitoTipAccount {
+		call.txn.tip = tipAccount[0]
+		call.txn.tipAmount += int64(lamports)
+	} else if isBloxRouteTipAccount {
+		call.txn.tip = tipAccount[1]
+		call.txn.tipAmount += int64(lamports)
+	}
+}
+
+// queueSwap holds a swap back until the compute budget and tips of the whole
+// transaction are known.
+func queueSwap(call *instructionCall, response generators.GeyserResponse) {
+	call.txn.swaps = append(call.txn.swaps, *call)
+}
+
+// processPoolInstruction handles pool creation and liquidity changes on AMM v4 and CPMM.
+func processPoolInstruction(call *instructionCall, response generators.GeyserResponse) {
+	var name string
+	var poolPos int
+
+	switch call.decoded.(type) {
+	case coder.Initialize2:
+		name, poolPos = "Initialize2", coder.AMM_INITIALIZE_POOL
+	case coder.CpmmInitialize:
+		name, poolPos = "CpmmInitialize", coder.CPMM_INITIALIZE_POOL
+	case coder.Deposit:
+		name, poolPos = "Deposit", 1
+	case coder.CpmmDeposit:
+		name, poolPos = "CpmmDeposit", coder.CPMM_LIQUIDITY_POOL
+	case coder.Withdraw:
+		name, poolPos = "Withdraw", 1
+	case coder.CpmmWithdraw:
+		name, poolPos = "CpmmWithdraw", coder.CPMM_LIQUIDITY_POOL
+	default:
+		return
+	}
+
+	log.Printf("%s | %s | %s | %s", name, response.MempoolTxns.Source, response.MempoolTxns.Signature, call.route)
+
+	ammId, err := getPublicKeyFromTx(poolPos, response.MempoolTxns, call.ins)
+	if err != nil {
+		log.Print("Unable to retrieve AMM ID")
+		return
+	}
+
+	switch decoded := call.decoded.(type) {
+	case coder.Initialize2:
+		processInitialize(ammId)
+		linkInitializedPool(ammId, coder.AMM_INITIALIZE_COIN_MINT, coder.AMM_INITIALIZE_PC_MINT, call.ins, response)
+		recordLpCreator(call.program, ammId, response)
+	case coder.CpmmInitialize:
+		processInitialize(ammId)
+		linkInitializedPool(ammId, coder.CPMM_INITIALIZE_TOKEN_0_MINT, coder.CPMM_INITIALIZE_TOKEN_1_MINT, call.ins, response)
+		recordLpCreator(call.program, ammId, response)
+	case coder.Deposit, coder.CpmmDeposit:
+		processDeposit(call.program, ammId, response)
+	case coder.Withdraw:
+		snapshotRemoval(call.program, ammId, decoded.Amount, response)
+		processWithdraw(call.program, ammId, decoded.Amount, response)
+	case coder.CpmmWithdraw:
+		snapshotRemoval(call.program, ammId, decoded.LpTokenAmount, response)
+		processWithdraw(call.program, ammId, decoded.LpTokenAmount, response)
 	}
 }
 
@@ -135,43 +262,45 @@ func getPublicKeyFromTx(pos int, tx generators.MempoolTxn, instruction generator
 		return nil, errors.New("no account indexes provided")
 	}
 
-	lookupsForAccountKeyIndex := GenerateTableLookup(tx.AddressTableLookups)
-	var ammId *solana.PublicKey
-	accountIndex := int(accountIndexes[pos])
+	if pos >= len(accountIndexes) {
+		return nil, errors.New("account position out of range")
+	}
 
-	if accountIndex >= len(tx.AccountKeys) {
-		lookupIndex := accountIndex - len(tx.AccountKeys)
-		lookup := lookupsForAccountKeyIndex[lookupIndex]
-		table, err := GetLookupTable(solana.MustPublicKeyFromBase58(lookup.LookupTableKey))
+	return getAccountKey(int(accountIndexes[pos]), tx)
+}
+
+// getAccountKey resolves an index into the transaction's account list, which is
+// the static account keys followed by addresses loaded from lookup tables.
+func getAccountKey(accountIndex int, tx generators.MempoolTxn) (*solana.PublicKey, error) {
+	if accountIndex < len(tx.AccountKeys) {
+		key, err := solana.PublicKeyFromBase58(tx.AccountKeys[accountIndex])
 		if err != nil {
 			return nil, err
 		}
+		return &key, nil
+	}
 
-		if int(lookup.LookupTableIndex) >= len(table.Addresses) {
-			return nil, errors.New("lookup table index out of range")
-		}
-
-		ammId = &table.Addresses[lookup.LookupTableIndex]
+	lookupsForAccountKeyIndex := GenerateTableLookup(tx.AddressTableLookups)
+	lookupIndex := accountIndex - len(tx.AccountKeys)
 
-	} else {
-		key := solana.MustPublicKeyFromBase58(tx.AccountKeys[accountIndex])
-		ammId = &key
+	if lookupIndex >= len(lookupsForAccountKeyIndex) {
+		return nil, errors.New("account index out of range")
 	}
 
-	return ammId, nil
-}
-
-func processInitialize2(ins generators.TxInstruction, tx generators.GeyserResponse) {
-	ammId, err := getPublicKeyFromTx(4, tx.MempoolTxns, ins)
+	lookup := lookupsForAccountKeyIndex[lookupIndex]
+	table, err := GetLookupTable(solana.MustPublicKeyFromBase58(lookup.LookupTableKey))
 	if err != nil {
-		return
+		return nil, err
 	}
 
-	if ammId == nil {
-		log.Print("Unable to retrieve AMM ID")
-		return
+	if int(lookup.LookupTableIndex) >= len(table.Addresses) {
+		return nil, errors.New("lookup table index out of range")
 	}
 
+	return &table.Addresses[lookup.LookupTableIndex], nil
+}
+
+func processInitialize(ammId *solana.PublicKey) {
 	tracker, err := GetAmmTrackingStatus(ammId)
 	if err != nil {
 		log.Print(err)
@@ -179,114 +308,345 @@ func processInitialize2(ins generators.TxInstruction, tx generators.GeyserRespon
 	}
 
 	if tracker.Status == storage.TRACKED_TRIGGER_ONLY || tracker.Status == storage.TRACKED_BOTH {
-		log.Printf("%s | Untracked because of initialize2", ammId)
+		log.Printf("%s | Untracked because of initialize", ammId)
 		PauseAmmTracking(ammId)
 	}
 }
 
-func processWithdraw(ins generators.TxInstruction, tx generators.GeyserResponse) {
-	ammId, err := getPublicKeyFromTx(1, tx.MempoolTxns, ins)
+// linkInitializedPool links a new pool to the pump.fun token it migrates.
+func linkInitializedPool(ammId *solana.PublicKey, baseMintPos int, quoteMintPos int, ins generators.TxInstruction, tx generators.GeyserResponse) {
+	if tx.MempoolTxns.Error != "" {
+		return
+	}
+
+	baseMint, err := getPublicKeyFromTx(baseMintPos, tx.MempoolTxns, ins)
 	if err != nil {
 		return
 	}
 
-	if ammId == nil {
-		log.Print("Unable to retrieve AMM ID")
+	quoteMint, err := getPublicKeyFromTx(quoteMintPos, tx.MempoolTxns, ins)
+	if err != nil {
 		return
 	}
 
-	pKey, err := liquidity.GetPoolKeys(ammId)
+	linkPumpToken(ammId, baseMint, quoteMint)
+}
+
+// processWithdraw measures a withdraw from the pool vaults in the transaction and
+// the LP tokens it burned, which are zero for pools without LP tokens.
+func processWithdraw(programId solana.PublicKey, ammId *solana.PublicKey, lpBurned uint64, tx generators.GeyserResponse) {
+	pKey, err := liquidity.GetPoolKeysByProgram(programId, ammId)
 	if err != nil {
 		log.Printf("%s | %s", ammId, err)
 		return
 	}
 
-	time.Sleep(time.Duration(500) * time.Millisecond)
+	var measure withdrawMeasure
+
+	for _, vault := range []solana.PublicKey{pKey.BaseVault, pKey.QuoteVault} {
+		if removed, ok := GetVaultRemovedShare(tx.MempoolTxns, vault); ok {
+			measure.RemovedPct = max(measure.RemovedPct, removed*100)
+		}
+	}
 
-	reserve, err := liquidity.GetPoolSolBalance(pKey)
+	if lpBurned > 0 {
+		supply, slot, err := getLpSupply(programId, ammId)
+		if err != nil {
+			log.Printf("%s | %s", ammId, err)
+		} else {
+			// Sources stream processed transactions and the pool is read at confirmed,
+			// so the supply normally predates the withdraw. A read that already
+			// includes it has the burned LP added back.
+			if slot >= tx.MempoolTxns.Slot {
+				supply += lpBurned
+			}
+			measure.LpBurnedPct = lpShare(lpBurned, supply)
+		}
+	}
+
+	if reserve, quote, err := getPoolReserve(pKey, tx); err == nil {
+		measure.Quote, measure.Reserve = &quote, reserve
+	}
+
+	ruleWithdraw(programId, ammId, pKey, tx, measure)
+}
+
+// checkPoolDrained tracks the pool once its quote reserve is down to the rug floor.
+func checkPoolDrained(programId solana.PublicKey, ammId *solana.PublicKey, pKey *types.RaydiumPoolKeys, tx generators.GeyserResponse) {
+	reserve, quote, err := getPoolReserve(pKey, tx)
 	if err != nil {
 		log.Printf("%s | %s", ammId, err)
 		return
 	}
 
-	if reserve > uint64(config.LAMPORTS_PER_SOL) {
+	if !belowRugFloor(reserve, quote) {
 		log.Printf("%s | Pool still have high balance", ammId)
 		return
 	}
 
-	TrackedAmm(ammId)
+	TrackedAmm(ammId, config.DexName(programId))
 }
 
-/**
-* Process swap base in instruction
- */
-func processSwapBaseIn(ins generators.TxInstruction, tx generators.GeyserResponse, computeLimit uint32, computePrice uint32, tip string, tipAmount int64, status string) {
-	var ammId *solana.PublicKey
-	var openbookId *solana.PublicKey
-	var sourceTokenAccount *solana.PublicKey
-	var destinationTokenAccount *solana.PublicKey
-	var signerPublicKey *solana.PublicKey
+// getPoolReserve returns the quote left in the pool after the transaction, in quote
+// units. The reserve is read from the transaction, and over RPC only when the quote
+// vault is not one of its accounts.
+func getPoolReserve(pKey *types.RaydiumPoolKeys, tx generators.GeyserResponse) (float64, types.QuoteMint, error) {
+	vault, quote, err := liquidity.GetQuoteVault(pKey)
+	if err != nil {
+		return 0, quote, err
+	}
+
+	reserve, ok := GetVaultPostBalance(tx.MempoolTxns, vault)
+	if !ok {
+		// Give the RPC node time to see the withdraw
+		time.Sleep(time.Duration(500) * time.Millisecond)
+
+		reserve, _, err = liquidity.GetPoolQuoteBalance(pKey)
+		if err != nil {
+			return 0, quote, err
+		}
+	}
 
-	var err error
-	ammId, err = getPublicKeyFromTx(1, tx.MempoolTxns, ins)
+	return liquidity.QuoteValue(new(big.Int).SetUint64(reserve), quote), quote, nil
+}
+
+func processDeposit(programId solana.PublicKey, ammId *solana.PublicKey, tx generators.GeyserResponse) {
+	pKey, err := liquidity.GetPoolKeysByProgram(programId, ammId)
 	if err != nil {
+		log.Printf("%s | %s", ammId, err)
 		return
 	}
 
-	if ammId == nil {
+	recordLiquidityEvent(storage.LIQUIDITY_ADD, ammId, pKey, tx, "", withdrawMeasure{})
+
+	tracker, err := GetAmmTrackingStatus(ammId)
+	if err != nil {
 		return
 	}
 
-	openbookId, err = getPublicKeyFromTx(7, tx.MempoolTxns, ins)
+	if tracker.Status == storage.TRACKED_TRIGGER_ONLY || tracker.Status == storage.TRACKED_BOTH {
+		log.Printf("%s | Liquidity added to tracked pool | %s", ammId, tx.MempoolTxns.Signature)
+	}
+}
+
+// recordLiquidityEvent stores how much the pool vaults and the fee payer's LP
+// balance moved in the transaction, with the classification of withdraws.
+func recordLiquidityEvent(action string, ammId *solana.PublicKey, pKey *types.RaydiumPoolKeys, tx generators.GeyserResponse, classification string, measure withdrawMeasure) {
+	if tx.MempoolTxns.Error != "" || len(tx.MempoolTxns.AccountKeys) == 0 {
+		return
+	}
+
+	signer := tx.MempoolTxns.AccountKeys[0]
+
+	baseAmount := GetVaultBalanceChange(tx.MempoolTxns, pKey.BaseVault)
+	quoteAmount := GetVaultBalanceChange(tx.MempoolTxns, pKey.QuoteVault)
+	lpAmount := GetOwnerBalanceChange(tx.MempoolTxns.PreTokenBalances, tx.MempoolTxns.PostTokenBalances, pKey.LpMint, signer)
+
+	event := &types.LiquidityEvent{
+		AmmId:          ammId,
+		Action:         action,
+		BaseAmount:     new(big.Int).Abs(baseAmount).String(),
+		QuoteAmount:    new(big.Int).Abs(quoteAmount).String(),
+		LpAmount:       new(big.Int).Abs(lpAmount).String(),
+		Signature:      tx.MempoolTxns.Signature,
+		Signer:         signer,
+		Slot:           tx.MempoolTxns.Slot,
+		Classification: classification,
+		RemovedPct:     measure.RemovedPct,
+		LpBurnedPct:    measure.LpBurnedPct,
+	}
+
+	if measure.Quote != nil {
+		event.QuoteMint = measure.Quote.Mint.String()
+		event.QuoteReserve = measure.Reserve
+		event.UsdReserve, _ = liquidity.UsdValue(measure.Reserve, *measure.Quote)
+	}
+
+	err := SetLiquidityEvent(event)
+
 	if err != nil {
+		log.Print(err)
+	}
+}
+
+/**
+* Process swaps on every pool program, in both the exact input and exact output variants.
+ */
+func processSwap(call instructionCall, tx generators.GeyserResponse, computeLimit uint32, computePrice uint64, priorityFee uint64, tip string, tipAmount int64, status string) {
+	var swapType string
+	var amountIn, amountOut uint64
+	var poolPositions []int
+	var signerPos int
+
+	switch decoded := call.decoded.(type) {
+	case coder.SwapBaseIn:
+		swapType = SWAP_BASE_IN
+		amountIn = decoded.AmountIn
+		amountOut = decoded.MinimumAmountOut
+	case coder.SwapBaseOut:
+		swapType = SWAP_BASE_OUT
+		amountIn = decoded.MaxAmountIn
+		amountOut = decoded.AmountOut
+	case coder.CpmmSwapBaseInput:
+		swapType = SWAP_BASE_IN
+		amountIn = decoded.AmountIn
+		amountOut = decoded.MinimumAmountOut
+		poolPositions, signerPos = []int{coder.CPMM_SWAP_POOL}, coder.CPMM_SWAP_PAYER
+	case coder.CpmmSwapBaseOutput:
+		swapType = SWAP_BASE_OUT
+		amountIn = decoded.MaxAmountIn
+		amountOut = decoded.AmountOut
+		poolPositions, signerPos = []int{coder.CPMM_SWAP_POOL}, coder.CPMM_SWAP_PAYER
+	case coder.WhirlpoolSwap:
+		swapType, amountIn, amountOut = whirlpoolSwapAmounts(decoded.AmountSpecifiedIsInput, decoded.Amount, decoded.OtherAmountThreshold)
+		poolPositions, signerPos = []int{coder.WHIRLPOOL_SWAP_POOL}, coder.WHIRLPOOL_SWAP_AUTHORITY
+		if decoded.V2 {
+			poolPositions, signerPos = []int{coder.WHIRLPOOL_SWAP_V2_POOL}, coder.WHIRLPOOL_SWAP_V2_AUTHORITY
+		}
+	case coder.WhirlpoolTwoHopSwap:
+		// Both pools are recorded, each with the amounts of its own leg
+		swapType, amountIn, amountOut = whirlpoolSwapAmounts(decoded.AmountSpecifiedIsInput, decoded.Amount, decoded.OtherAmountThreshold)
+		poolPositions, signerPos = []int{coder.WHIRLPOOL_TWO_HOP_POOL_ONE, coder.WHIRLPOOL_TWO_HOP_POOL_TWO}, coder.WHIRLPOOL_TWO_HOP_AUTHORITY
+	default:
 		return
 	}
 
-	var sourceAccountIndex int
-	var destinationAccountIndex int
-	var signerAccountIndex int
+	var ammIds []*solana.PublicKey
+	var signerPublicKey *solana.PublicKey
 
-	if openbookId.String() == config.OPENBOOK_ID.String() {
-		sourceAccountIndex = 15
-		destinationAccountIndex = 16
-		signerAccountIndex = 17
+	if poolPositions == nil {
+		ammId, signer, err := getAmmSwapAccounts(call.ins, tx)
+		if err != nil {
+			return
+		}
+		ammIds, signerPublicKey = []*solana.PublicKey{ammId}, signer
 	} else {
-		sourceAccountIndex = 14
-		destinationAccountIndex = 15
-		signerAccountIndex = 16
+		for _, pos := range poolPositions {
+			ammId, err := getPublicKeyFromTx(pos, tx.MempoolTxns, call.ins)
+			if err != nil {
+				return
+			}
+			ammIds = append(ammIds, ammId)
+		}
+
+		var err error
+		signerPublicKey, err = getPublicKeyFromTx(signerPos, tx.MempoolTxns, call.ins)
+		if err != nil {
+			return
+		}
 	}
 
-	if sourceAccountIndex >= len(ins.Accounts) || destinationAccountIndex >= len(ins.Accounts) || signerAccountIndex >= len(ins.Accounts) {
-		log.Printf("%s | Invalid data length (%d)", ammId, len(ins.Accounts))
-		return
+	var legs []swapLeg
+	if len(ammIds) > 1 {
+		for _, ammId := range ammIds {
+			leg, err := getSwapLeg(call.program, ammId, tx)
+			if err != nil {
+				log.Printf("%s | %s", ammId, err)
+				return
+			}
+			legs = append(legs, leg)
+		}
 	}
 
-	sourceTokenAccount, _ = getPublicKeyFromTx(sourceAccountIndex, tx.MempoolTxns, ins)
-	destinationTokenAccount, _ = getPublicKeyFromTx(destinationAccountIndex, tx.MempoolTxns, ins)
-	signerPublicKey, _ = getPublicKeyFromTx(signerAccountIndex, tx.MempoolTxns, ins)
+	for i, ammId := range ammIds {
+		trade := &types.Trade{
+			AmmId:           ammId,
+			Dex:             config.DexName(call.program),
+			ComputeLimit:    uint64(computeLimit),
+			ComputePrice:    computePrice,
+			PriorityFee:     priorityFee,
+			ComputeConsumed: tx.MempoolTxns.ComputeUnitsConsumed,
+			ConsumedFee:     coder.PriorityFee(tx.MempoolTxns.ComputeUnitsConsumed, computePrice),
+			Signature:       tx.MempoolTxns.Signature,
+			Tip:             tip,
+			TipAmount:       tipAmount,
+			Status:          status,
+			Signer:          signerPublicKey.String(),
+			Route:           call.route,
+			OuterProgram:    call.outerProgram,
+			SwapType:        swapType,
+			AmountIn:        amountIn,
+			AmountOut:       amountOut,
+		}
+
+		if legs != nil {
+			trade.AmountIn, trade.AmountOut = legs[i].amountIn, legs[i].amountOut
+			trade.IntermediateMint = legs[0].mintOut.String()
+		}
 
-	if sourceTokenAccount == nil || destinationTokenAccount == nil || signerPublicKey == nil {
+		recordSwap(call.program, trade, tx)
+	}
+}
+
+// swapLeg is what one pool of a multi-hop swap took in and paid out.
+type swapLeg struct {
+	amountIn  uint64
+	amountOut uint64
+	mintOut   solana.PublicKey
+}
+
+// getSwapLeg measures the leg of a multi-hop swap on a pool from its vault balance
+// changes. The vault that grew took the input and the one that shrank paid out.
+func getSwapLeg(programId solana.PublicKey, ammId *solana.PublicKey, tx generators.GeyserResponse) (swapLeg, error) {
+	pKey, err := liquidity.GetPoolKeysByProgram(programId, ammId)
+	if err != nil {
+		return swapLeg{}, err
+	}
+
+	// Changes are pre minus post, so the paid out vault is positive
+	base := GetVaultBalanceChange(tx.MempoolTxns, pKey.BaseVault)
+	quote := GetVaultBalanceChange(tx.MempoolTxns, pKey.QuoteVault)
+
+	switch {
+	case base.Sign() > 0 && quote.Sign() < 0:
+		return swapLeg{amountIn: new(big.Int).Neg(quote).Uint64(), amountOut: base.Uint64(), mintOut: pKey.BaseMint}, nil
+	case quote.Sign() > 0 && base.Sign() < 0:
+		return swapLeg{amountIn: new(big.Int).Neg(base).Uint64(), amountOut: quote.Uint64(), mintOut: pKey.QuoteMint}, nil
+	default:
+		return swapLeg{}, errors.New("pool vaults did not move in opposite directions")
+	}
+}
+
+// whirlpoolSwapAmounts maps a whirlpool swap onto the Raydium swap variants.
+func whirlpoolSwapAmounts(amountSpecifiedIsInput bool, amount uint64, otherAmountThreshold uint64) (string, uint64, uint64) {
+	if amountSpecifiedIsInput {
+		return SWAP_BASE_IN, amount, otherAmountThreshold
+	}
+	return SWAP_BASE_OUT, otherAmountThreshold, amount
+}
+
+// recordSwap stores a swap on a tracked pool, classified as a BUY or SELL of the
+// pool's token from the pool vault's balance change.
+func recordSwap(programId solana.PublicKey, trade *types.Trade, tx generators.GeyserResponse) {
+	ammId := trade.AmmId
+
+	pKey, err := liquidity.GetPoolKeysByProgram(programId, ammId)
+	if err != nil {
 		return
 	}
 
-	pKey, err := liquidity.GetPoolKeys(ammId)
+	mint, swap, err := liquidity.GetMint(pKey)
 	if err != nil {
 		return
 	}
 
-	mint, _, err := liquidity.GetMint(pKey)
+	quote, _, err := liquidity.GetQuote(pKey)
 	if err != nil {
 		return
 	}
 
+	mintVault, quoteVault := pKey.BaseVault, pKey.QuoteVault
+	if swap {
+		mintVault, quoteVault = quoteVault, mintVault
+	}
+
 	tracker, _ := GetAmmTrackingStatus(ammId)
 
 	if tracker.Status != storage.TRACKED_TRIGGER_ONLY {
 		return
 	}
 
-	amount := GetBalanceFromTransaction(tx.MempoolTxns.PreTokenBalances, tx.MempoolTxns.PostTokenBalances, mint)
-	// amountSol := GetBalanceFromTransaction(tx.MempoolTxns.PreTokenBalances, tx.MempoolTxns.PostTokenBalances, config.WRAPPED_SOL)
+	amount := GetVaultBalanceChange(tx.MempoolTxns, mintVault)
 
 	var action string = "SELL"
 
@@ -296,22 +656,18 @@ func processSwapBaseIn(ins generators.TxInstruction, tx generators.GeyserRespons
 		}
 	}
 
-	if tip == "" {
-		tip = sql.NullString{}.String
-	}
+	trade.Mint = &mint
+	trade.Action = action
+	trade.Amount = amount.String()
+
+	quoteAmount := new(big.Int).Abs(GetVaultBalanceChange(tx.MempoolTxns, quoteVault))
+	trade.QuoteMint = quote.Mint.String()
+	trade.QuoteAmount = quoteAmount.String()
+	trade.QuoteValue = liquidity.QuoteValue(quoteAmount, quote)
+	trade.UsdValue, _ = liquidity.UsdValue(trade.QuoteValue, quote)
 
-	trade := &types.Trade{
-		AmmId:        ammId,
-		Mint:         &mint,
-		Action:       action,
-		ComputeLimit: uint64(computeLimit),
-		ComputePrice: uint64(computePrice),
-		Amount:       amount.String(),
-		Signature:    tx.MempoolTxns.Signature,
-		Tip:          tip,
-		TipAmount:    tipAmount,
-		Status:       status,
-		Signer:       signerPublicKey.String(),
+	if trade.Tip == "" {
+		trade.Tip = sql.NullString{}.String
 	}
 
 	err = SetTrade(trade)
@@ -320,7 +676,7 @@ func processSwapBaseIn(ins generators.TxInstruction, tx generators.GeyserRespons
 		log.Print(err)
 	}
 
-	log.Printf("%s | %s | %s | %d | %d | %d | %s", ammId, tx.MempoolTxns.Signature, action, computeLimit, computePrice, amount, tip)
+	log.Printf("%s | %s | %s | %s | %d | %d | %d | %d | %s | %s", ammId, tx.MempoolTxns.Signature, trade.SwapType, action, trade.ComputeLimit, trade.ComputePrice, trade.PriorityFee, amount, trade.Tip, trade.Route)
 
 	/* 	if amount.Sign() == 1 {
 	   		if amountSol.Cmp(big.NewInt(0)) == 1 {
@@ -343,3 +699,30 @@ func processSwapBaseIn(ins generators.TxInstruction, tx generators.GeyserRespons
 	// Machine gun technique
 	// Sniper technique
 }
+
+// getAmmSwapAccounts returns the pool and signer of an AMM v4 swap. The user
+// accounts shift by one when the swap carries the OpenBook market accounts.
+func getAmmSwapAccounts(ins generators.TxInstruction, tx generators.GeyserResponse) (*solana.PublicKey, *solana.PublicKey, error) {
+	ammId, err := getPublicKeyFromTx(1, tx.MempoolTxns, ins)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	openbookId, err := getPublicKeyFromTx(7, tx.MempoolTxns, ins)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signerAccountIndex := 16
+	if *openbookId == config.OPENBOOK_ID {
+		signerAccountIndex = 17
+	}
+
+	signerPublicKey, err := getPublicKeyFromTx(signerAccountIndex, tx.MempoolTxns, ins)
+	if err != nil {
+		log.Printf("%s | Invalid data length (%d)", ammId, len(ins.Accounts))
+		return nil, nil, err
+	}
+
+	return ammId, signerPublicKey, nil
+}
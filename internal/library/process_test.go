@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+)
+
+func TestInvocationStack(t *testing.T) {
+	var (
+		router = solana.MustPublicKeyFromBase58("JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4")
+		token  = config.TOKEN_PROGRAM_ID
+		amm    = config.RAYDIUM_AMM_V4
+	)
+
+	type step struct {
+		program solana.PublicKey
+		height  uint32
+		caller  solana.PublicKey
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			// router -> amm -> token, token; router -> token
+			name: "stack height",
+			steps: []step{
+				{amm, 2, router},
+				{token, 3, amm},
+				{token, 3, amm},
+				{token, 2, router},
+			},
+		},
+		{
+			// Without heights the router's own transfer after the swap is taken to be
+			// invoked by the AMM, the last program that invokes others
+			name: "no stack height",
+			steps: []step{
+				{amm, 0, router},
+				{token, 0, amm},
+				{token, 0, amm},
+				{token, 0, amm},
+			},
+		},
+		{
+			name: "height past the open invocations",
+			steps: []step{
+				{amm, 2, router},
+				{token, 5, amm},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stack := newInvocationStack(router)
+			for i, s := range tt.steps {
+				if got := stack.invoke(s.program, s.height); got != s.caller {
+					t.Errorf("step %d: caller = %s, want %s", i, got, s.caller)
+				}
+			}
+		})
+	}
+}
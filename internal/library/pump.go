@@ -0,0 +1,137 @@
+package bot
+
+import (
+	"log"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/adapter"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/coder"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/generators"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/liquidity"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/storage"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+)
+
+func init() {
+	decoders.Register(config.PUMP_FUN, coder.NewPumpCoder())
+	registerHandler(processPumpInstruction, coder.PumpCreate{}, coder.PumpWithdraw{}, coder.PumpMigrate{})
+}
+
+// processPumpInstruction records pump.fun launches and migrations. Buys and sells on
+// the bonding curve are decoded but not stored.
+func processPumpInstruction(call *instructionCall, tx generators.GeyserResponse) {
+	if tx.MempoolTxns.Error != "" {
+		return
+	}
+
+	switch decoded := call.decoded.(type) {
+	case coder.PumpCreate:
+		processPumpCreate(call.ins, decoded, tx)
+	case coder.PumpWithdraw, coder.PumpMigrate:
+		processPumpMigrate(call.ins, tx)
+	}
+}
+
+func processPumpCreate(ins generators.TxInstruction, decoded coder.PumpCreate, tx generators.GeyserResponse) {
+	mint, err := getPublicKeyFromTx(coder.PUMP_CREATE_MINT, tx.MempoolTxns, ins)
+	if err != nil {
+		return
+	}
+
+	bondingCurve, err := getPublicKeyFromTx(coder.PUMP_CREATE_BONDING_CURVE, tx.MempoolTxns, ins)
+	if err != nil {
+		return
+	}
+
+	creator := decoded.Creator
+	if creator == nil {
+		creator, err = getPublicKeyFromTx(coder.PUMP_CREATE_USER, tx.MempoolTxns, ins)
+		if err != nil {
+			return
+		}
+	}
+
+	log.Printf("PumpCreate | %s | %s | %s | %s", mint, creator, decoded.Symbol, tx.MempoolTxns.Signature)
+
+	err = SetPumpToken(&types.PumpToken{
+		Mint:             mint,
+		BondingCurve:     bondingCurve,
+		Creator:          creator,
+		Name:             decoded.Name,
+		Symbol:           decoded.Symbol,
+		Uri:              decoded.Uri,
+		CreatedSlot:      tx.MempoolTxns.Slot,
+		CreatedSignature: tx.MempoolTxns.Signature,
+	})
+
+	if err != nil {
+		log.Print(err)
+	}
+}
+
+func processPumpMigrate(ins generators.TxInstruction, tx generators.GeyserResponse) {
+	mint, err := getPublicKeyFromTx(coder.PUMP_MIGRATE_MINT, tx.MempoolTxns, ins)
+	if err != nil {
+		return
+	}
+
+	log.Printf("PumpMigrate | %s | %s", mint, tx.MempoolTxns.Signature)
+
+	db, err := adapter.GetMySQLClient()
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	dbMutex.Lock()
+	defer dbMutex.Unlock()
+
+	if err := storage.NewPumpStorage(db).SetMigrated(mint, tx.MempoolTxns.Signature, tx.MempoolTxns.Slot); err != nil {
+		log.Print(err)
+	}
+}
+
+// linkPumpToken links a new Raydium pool to the pump.fun token it was created for,
+// the side that is not a quote mint. Pools of other tokens are ignored.
+func linkPumpToken(ammId *solana.PublicKey, baseMint *solana.PublicKey, quoteMint *solana.PublicKey) {
+	// The pool is too new to have its keys stored, and only the mints are needed
+	mint, _, err := liquidity.GetMint(&types.RaydiumPoolKeys{BaseMint: *baseMint, QuoteMint: *quoteMint})
+	if err != nil {
+		return
+	}
+
+	db, err := adapter.GetMySQLClient()
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	dbMutex.Lock()
+	defer dbMutex.Unlock()
+
+	linked, err := storage.NewPumpStorage(db).SetAmm(&mint, ammId)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	if linked {
+		log.Printf("%s | Migrated pump.fun token %s", ammId, mint)
+	}
+}
+
+func SetPumpToken(token *types.PumpToken) error {
+	db, err := adapter.GetMySQLClient()
+	if err != nil {
+		log.Printf("Failed to get initialize mysql instance: %v", err)
+		return err
+	}
+
+	dbMutex.Lock()
+	defer dbMutex.Unlock()
+
+	token.Timestamp = time.Now().Unix()
+	return storage.NewPumpStorage(db).Set(token)
+}
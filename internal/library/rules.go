@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/generators"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/liquidity"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/storage"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+)
+
+// withdrawMeasure is what a single withdraw took out of a pool. Percentages are of
+// the reserves and the LP supply before the withdraw. Reserve is the quote left in
+// the pool in quote units, when Quote is known.
+type withdrawMeasure struct {
+	RemovedPct  float64
+	LpBurnedPct float64
+	Quote       *types.QuoteMint
+	Reserve     float64
+}
+
+// classifyWithdraw applies the configured rug thresholds to a withdraw.
+func classifyWithdraw(measure withdrawMeasure) string {
+	rug := config.Rug
+
+	switch {
+	case measure.Quote != nil && belowRugFloor(measure.Reserve, *measure.Quote),
+		measure.RemovedPct >= rug.RemovedPct,
+		measure.LpBurnedPct >= rug.LpBurnedPct:
+		return storage.WITHDRAW_FULL_RUG
+	case measure.RemovedPct >= rug.PartialPct,
+		measure.LpBurnedPct >= rug.PartialPct:
+		return storage.WITHDRAW_PARTIAL_PULL
+	default:
+		return storage.WITHDRAW_ROUTINE
+	}
+}
+
+// belowRugFloor reports whether a reserve in quote units is at or below the rug floor,
+// which is set in SOL. Reserves that can't be valued in SOL never are.
+func belowRugFloor(reserve float64, quote types.QuoteMint) bool {
+	sol, ok := liquidity.SolValue(reserve, quote)
+	return ok && sol <= config.Rug.Floor
+}
+
+// ruleWithdraw classifies a withdraw, stores it with its classification and tracks
+// the pool when the withdraw is a full rug.
+func ruleWithdraw(programId solana.PublicKey, ammId *solana.PublicKey, pKey *types.RaydiumPoolKeys, tx generators.GeyserResponse, measure withdrawMeasure) {
+	// A failed withdraw took nothing out
+	if tx.MempoolTxns.Error != "" {
+		return
+	}
+
+	classification := classifyWithdraw(measure)
+
+	recordLiquidityEvent(storage.LIQUIDITY_REMOVE, ammId, pKey, tx, classification, measure)
+
+	log.Printf("%s | %s | Removed %.2f%% of reserves, burned %.2f%% of LP | %s", ammId, classification, measure.RemovedPct, measure.LpBurnedPct, tx.MempoolTxns.Signature)
+
+	if classification != storage.WITHDRAW_FULL_RUG {
+		return
+	}
+
+	TrackedAmm(ammId, config.DexName(programId))
+}
@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/price"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/storage"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+)
+
+func TestClassifyWithdraw(t *testing.T) {
+	var (
+		wsol = &types.QuoteMint{Mint: config.WRAPPED_SOL, Symbol: "SOL", Decimals: 9}
+		usdc = &types.QuoteMint{Mint: solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"), Symbol: "USDC", Decimals: 6, Usd: true}
+		jup  = &types.QuoteMint{Mint: solana.MustPublicKeyFromBase58("JUPyiwrYJFskUPiHa7hkeR8VUtAeFoSYbKedZNsDvCN"), Symbol: "JUP", Decimals: 6}
+	)
+
+	rug := config.Rug
+	config.Rug = config.RugConfig{Floor: 1, RemovedPct: 80, LpBurnedPct: 80, PartialPct: 20}
+	defer func() { config.Rug = rug }()
+
+	tests := []struct {
+		name    string
+		source  price.Source
+		measure withdrawMeasure
+		want    string
+	}{
+		{"nothing removed", nil, withdrawMeasure{}, storage.WITHDRAW_ROUTINE},
+		{"just under partial", nil, withdrawMeasure{RemovedPct: 19.99, LpBurnedPct: 19.99}, storage.WITHDRAW_ROUTINE},
+		{"removed at partial", nil, withdrawMeasure{RemovedPct: 20}, storage.WITHDRAW_PARTIAL_PULL},
+		{"burned at partial", nil, withdrawMeasure{LpBurnedPct: 20}, storage.WITHDRAW_PARTIAL_PULL},
+		{"just under removed", nil, withdrawMeasure{RemovedPct: 79.99}, storage.WITHDRAW_PARTIAL_PULL},
+		{"removed at threshold", nil, withdrawMeasure{RemovedPct: 80}, storage.WITHDRAW_FULL_RUG},
+		{"just under burned", nil, withdrawMeasure{LpBurnedPct: 79.99}, storage.WITHDRAW_PARTIAL_PULL},
+		{"burned at threshold", nil, withdrawMeasure{LpBurnedPct: 80}, storage.WITHDRAW_FULL_RUG},
+
+		{"SOL left at the floor", nil, withdrawMeasure{RemovedPct: 5, Quote: wsol, Reserve: 1}, storage.WITHDRAW_FULL_RUG},
+		{"SOL left above the floor", nil, withdrawMeasure{RemovedPct: 5, Quote: wsol, Reserve: 1.01}, storage.WITHDRAW_ROUTINE},
+		{"no quote skips the floor", nil, withdrawMeasure{RemovedPct: 5, Reserve: 0}, storage.WITHDRAW_ROUTINE},
+		// 150 USDC is 0.75 SOL at 200 USD
+		{"stablecoin below the floor", price.NewStaticSource(200), withdrawMeasure{RemovedPct: 5, Quote: usdc, Reserve: 150}, storage.WITHDRAW_FULL_RUG},
+		{"stablecoin above the floor", price.NewStaticSource(200), withdrawMeasure{RemovedPct: 5, Quote: usdc, Reserve: 250}, storage.WITHDRAW_ROUTINE},
+		{"stablecoin without a price", nil, withdrawMeasure{RemovedPct: 5, Quote: usdc, Reserve: 0}, storage.WITHDRAW_ROUTINE},
+		// A quote that can't be valued in SOL is classified by percentages alone
+		{"unvalued quote emptied", price.NewStaticSource(200), withdrawMeasure{RemovedPct: 5, Quote: jup, Reserve: 0}, storage.WITHDRAW_ROUTINE},
+		{"unvalued quote partial", price.NewStaticSource(200), withdrawMeasure{RemovedPct: 50, Quote: jup, Reserve: 0}, storage.WITHDRAW_PARTIAL_PULL},
+		{"unvalued quote removed", nil, withdrawMeasure{RemovedPct: 90, Quote: jup, Reserve: 1_000}, storage.WITHDRAW_FULL_RUG},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price.SetSource(tt.source)
+			defer price.SetSource(nil)
+
+			if got := classifyWithdraw(tt.measure); got != tt.want {
+				t.Errorf("classifyWithdraw(%+v) = %s, want %s", tt.measure, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"log"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/generators"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/liquidity"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/storage"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+)
+
+const (
+	SNAPSHOT_BEFORE = "before"
+	SNAPSHOT_AFTER  = "after"
+)
+
+// snapshotRemoval stores the pool's state before and after a removal, valued from the
+// vault balances of the transaction itself.
+func snapshotRemoval(programId solana.PublicKey, ammId *solana.PublicKey, lpBurned uint64, tx generators.GeyserResponse) {
+	if tx.MempoolTxns.Error != "" {
+		return
+	}
+
+	pKey, err := liquidity.GetPoolKeysByProgram(programId, ammId)
+	if err != nil {
+		log.Printf("%s | Failed to snapshot pool: %v", ammId, err)
+		return
+	}
+
+	preBase, okPreBase := getVaultBalance(tx.MempoolTxns, tx.MempoolTxns.PreTokenBalances, pKey.BaseVault)
+	preQuote, okPreQuote := getVaultBalance(tx.MempoolTxns, tx.MempoolTxns.PreTokenBalances, pKey.QuoteVault)
+	postBase, okPostBase := getVaultBalance(tx.MempoolTxns, tx.MempoolTxns.PostTokenBalances, pKey.BaseVault)
+	postQuote, okPostQuote := getVaultBalance(tx.MempoolTxns, tx.MempoolTxns.PostTokenBalances, pKey.QuoteVault)
+	if !okPreBase || !okPreQuote || !okPostBase || !okPostQuote {
+		log.Printf("%s | Failed to snapshot pool: vaults not in transaction balances", ammId)
+		return
+	}
+
+	fees, err := liquidity.GetPoolFees(programId, ammId)
+	if err != nil {
+		log.Printf("%s | Failed to snapshot pool: %v", ammId, err)
+		return
+	}
+
+	// The pool is read at confirmed while sources stream processed transactions, so
+	// its LP supply is from before the removal unless it was read at the removal's
+	// slot or later
+	preSupply, postSupply := fees.LpSupply, fees.LpSupply-min(lpBurned, fees.LpSupply)
+	if fees.Slot >= tx.MempoolTxns.Slot {
+		preSupply, postSupply = fees.LpSupply+lpBurned, fees.LpSupply
+	}
+
+	before := liquidity.NewPoolSnapshot(pKey, ammId, preBase, preQuote, preSupply, fees)
+	before.Stage = SNAPSHOT_BEFORE
+
+	after := liquidity.NewPoolSnapshot(pKey, ammId, postBase, postQuote, postSupply, fees)
+	after.Stage = SNAPSHOT_AFTER
+
+	for _, snapshot := range []*types.PoolSnapshot{before, after} {
+		snapshot.Slot = tx.MempoolTxns.Slot
+		snapshot.Signature = tx.MempoolTxns.Signature
+
+		if err := SetPoolSnapshot(snapshot); err != nil {
+			log.Print(err)
+		}
+	}
+}
+
+func SetPoolSnapshot(snapshot *types.PoolSnapshot) error {
+	dbMutex.Lock()
+	defer dbMutex.Unlock()
+
+	snapshot.Timestamp = time.Now().Unix()
+	return storage.Snapshot.Set(snapshot)
+}
@@ -0,0 +1,129 @@
+package bot
+
+import (
+	"log"
+	"time"
+
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/adapter"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/coder"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/generators"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/storage"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+)
+
+func init() {
+	decoders.Register(config.TOKEN_PROGRAM_ID, coder.NewSplTokenCoder())
+	decoders.Register(config.TOKEN_2022_PROGRAM_ID, coder.NewToken2022Coder())
+	registerHandler(processTokenRiskInstruction,
+		coder.TokenSetAuthority{}, coder.TokenMintTo{}, coder.TokenMintToChecked{}, coder.TokenFreezeAccount{},
+		coder.TokenSetTransferFee{}, coder.TokenUpdateTransferHook{})
+}
+
+// processTokenRiskInstruction flags the use of mint authorities on the token of a
+// tracked pool: minting, freezing, handing over or dropping the mint and freeze
+// authorities, and changing the Token-2022 transfer fee or transfer hook.
+func processTokenRiskInstruction(call *instructionCall, response generators.GeyserResponse) {
+	if response.MempoolTxns.Error != "" {
+		return
+	}
+
+	var (
+		event      string
+		mintPos    int
+		accountPos = -1
+		account    string
+		amount     uint64
+	)
+
+	switch decoded := call.decoded.(type) {
+	case coder.TokenSetAuthority:
+		switch decoded.AuthorityType {
+		case coder.TOKEN_AUTHORITY_MINT_TOKENS:
+			event = storage.RISK_MINT_AUTHORITY_CHANGED
+		case coder.TOKEN_AUTHORITY_FREEZE_ACCOUNT:
+			event = storage.RISK_FREEZE_AUTHORITY_CHANGED
+		default:
+			return
+		}
+
+		mintPos = coder.TOKEN_SET_AUTHORITY_ACCOUNT
+		if decoded.NewAuthority != nil {
+			account = decoded.NewAuthority.String()
+		}
+	case coder.TokenMintTo:
+		event, mintPos, accountPos, amount = storage.RISK_MINTED, coder.TOKEN_MINT_TO_MINT, coder.TOKEN_MINT_TO_DESTINATION, decoded.Amount
+	case coder.TokenMintToChecked:
+		event, mintPos, accountPos, amount = storage.RISK_MINTED, coder.TOKEN_MINT_TO_MINT, coder.TOKEN_MINT_TO_DESTINATION, decoded.Amount
+	case coder.TokenFreezeAccount:
+		event, mintPos, accountPos = storage.RISK_ACCOUNT_FROZEN, coder.TOKEN_FREEZE_ACCOUNT_MINT, coder.TOKEN_FREEZE_ACCOUNT_ACCOUNT
+	case coder.TokenSetTransferFee:
+		event, mintPos, amount = storage.RISK_TRANSFER_FEE_CHANGED, coder.TOKEN_SET_TRANSFER_FEE_MINT, uint64(decoded.TransferFeeBasisPoints)
+	case coder.TokenUpdateTransferHook:
+		event, mintPos = storage.RISK_TRANSFER_HOOK_CHANGED, coder.TOKEN_UPDATE_TRANSFER_HOOK_MINT
+		if decoded.ProgramId != nil {
+			account = decoded.ProgramId.String()
+		}
+	default:
+		return
+	}
+
+	mint, err := getPublicKeyFromTx(mintPos, response.MempoolTxns, call.ins)
+	if err != nil {
+		return
+	}
+
+	redisClient, err := adapter.GetRedisClient(4)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	ammId, err := storage.GetMintPool(redisClient, mint)
+	if err != nil {
+		return
+	}
+
+	tracker, err := GetAmmTrackingStatus(ammId)
+	if err != nil || (tracker.Status != storage.TRACKED_TRIGGER_ONLY && tracker.Status != storage.TRACKED_BOTH) {
+		return
+	}
+
+	if accountPos >= 0 {
+		key, err := getPublicKeyFromTx(accountPos, response.MempoolTxns, call.ins)
+		if err != nil {
+			return
+		}
+		account = key.String()
+	}
+
+	log.Printf("%s | %s on %s | %s | %d | %s", ammId, event, mint, account, amount, response.MempoolTxns.Signature)
+
+	err = SetTokenRiskEvent(&types.TokenRiskEvent{
+		AmmId:     ammId,
+		Mint:      mint,
+		Event:     event,
+		Account:   account,
+		Amount:    amount,
+		Signature: response.MempoolTxns.Signature,
+		Slot:      response.MempoolTxns.Slot,
+	})
+
+	if err != nil {
+		log.Print(err)
+	}
+}
+
+func SetTokenRiskEvent(event *types.TokenRiskEvent) error {
+	db, err := adapter.GetMySQLClient()
+	if err != nil {
+		log.Printf("Failed to get initialize mysql instance: %v", err)
+		return err
+	}
+
+	dbMutex.Lock()
+	defer dbMutex.Unlock()
+
+	event.Timestamp = time.Now().Unix()
+	return storage.NewRiskStorage(db).Set(event)
+}
@@ -6,15 +6,20 @@ import (
 
 	"github.com/gagliardetto/solana-go"
 	"github.com/iqbalbaharum/lp-remove-tracker/internal/adapter"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/generators"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/liquidity"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/rpc"
 	"github.com/iqbalbaharum/lp-remove-tracker/internal/storage"
 	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+	"github.com/redis/go-redis/v9"
 )
 
 func trackedInit() {
 
 }
 
-func TrackedAmm(ammId *solana.PublicKey) {
+func TrackedAmm(ammId *solana.PublicKey, dex string) {
 	redisClient, err := adapter.GetRedisClient(4)
 	if err != nil {
 		log.Fatalf("Failed to get initialize redis instance: %v", err)
@@ -22,10 +27,127 @@ func TrackedAmm(ammId *solana.PublicKey) {
 
 	var tracker types.Tracker = types.Tracker{}
 	tracker.AmmId = ammId
+	tracker.Dex = dex
 
 	tracker.Status = storage.TRACKED_TRIGGER_ONLY
 
 	storage.SetTracked(redisClient, ammId.String(), tracker)
+
+	// The mint is read over RPC, which must not hold up the transaction that
+	// triggered tracking
+	go profileTrackedMint(redisClient, ammId)
+}
+
+// profileTrackedMint keeps the pool of the tracked token, so instructions of the token
+// program on its mint can be linked back to the pool, and reads the mint to record
+// what its authorities can still do.
+func profileTrackedMint(redisClient *redis.Client, ammId *solana.PublicKey) {
+	pKey, err := storage.GetPoolKeys(redisClient, ammId)
+	if err != nil {
+		return
+	}
+
+	mint, _, err := liquidity.GetMint(pKey)
+	if err != nil {
+		return
+	}
+
+	watchPoolMints(pKey, mint)
+
+	if err := storage.SetMintPool(redisClient, &mint, ammId); err != nil {
+		log.Print(err)
+	}
+
+	state, program, err := rpc.GetTokenMint(&mint)
+	if err != nil {
+		log.Printf("%s | Failed to read mint %s: %v", ammId, mint, err)
+		return
+	}
+
+	profile := &types.MintRiskProfile{
+		AmmId:             ammId,
+		Mint:              &mint,
+		TokenProgram:      program.String(),
+		Token2022:         program == config.TOKEN_2022_PROGRAM_ID,
+		MintAuthority:     state.MintAuthority,
+		FreezeAuthority:   state.FreezeAuthority,
+		Supply:            state.Supply,
+		Decimals:          state.Decimals,
+		TransferHook:      state.TransferHook,
+		PermanentDelegate: state.PermanentDelegate,
+		LastUpdated:       time.Now().Unix(),
+	}
+
+	if state.TransferFee != nil {
+		profile.TransferFee = true
+		profile.TransferFeeBasisPoints = state.TransferFee.TransferFeeBasisPoints
+		profile.MaximumFee = state.TransferFee.MaximumFee
+	}
+
+	if err := storage.SetMintRisk(redisClient, profile); err != nil {
+		log.Print(err)
+	}
+}
+
+// watchPoolMints streams the transactions of the token and LP mints of a tracked pool,
+// as their authorities and holders act through the token programs.
+func watchPoolMints(pKey *types.RaydiumPoolKeys, mint solana.PublicKey) {
+	generators.Watched.Add(mint.String(), 0)
+	if !pKey.LpMint.IsZero() {
+		generators.Watched.Add(pKey.LpMint.String(), 0)
+	}
+}
+
+// unwatchPoolMints stops streaming the mints of a pool that is no longer tracked.
+func unwatchPoolMints(redisClient *redis.Client, ammId *solana.PublicKey) {
+	pKey, err := storage.GetPoolKeys(redisClient, ammId)
+	if err != nil {
+		return
+	}
+
+	mint, _, err := liquidity.GetMint(pKey)
+	if err != nil {
+		return
+	}
+
+	generators.Watched.Remove(mint.String())
+	if !pKey.LpMint.IsZero() {
+		generators.Watched.Remove(pKey.LpMint.String())
+	}
+}
+
+// WatchTrackedMints streams the mints of the pools that were tracked before the
+// bot started.
+func WatchTrackedMints() error {
+	redisClient, err := adapter.GetRedisClient(4)
+	if err != nil {
+		return err
+	}
+
+	trackers, err := storage.GetAllTracked(redisClient)
+	if err != nil {
+		return err
+	}
+
+	for _, tracker := range *trackers {
+		if tracker.AmmId == nil || tracker.Status == storage.NOT_TRACKED {
+			continue
+		}
+
+		pKey, err := storage.GetPoolKeys(redisClient, tracker.AmmId)
+		if err != nil {
+			continue
+		}
+
+		mint, _, err := liquidity.GetMint(pKey)
+		if err != nil {
+			continue
+		}
+
+		watchPoolMints(pKey, mint)
+	}
+
+	return nil
 }
 
 func PauseAmmTracking(ammId *solana.PublicKey) {
@@ -36,6 +158,7 @@ func PauseAmmTracking(ammId *solana.PublicKey) {
 
 	var tracker types.Tracker = types.Tracker{
 		AmmId:       ammId,
+		Dex:         getTrackedDex(redisClient, ammId),
 		Status:      storage.PAUSE,
 		LastUpdated: time.Now().Unix(),
 	}
@@ -51,11 +174,23 @@ func UntrackedAmm(ammId *solana.PublicKey) {
 
 	var tracker types.Tracker = types.Tracker{
 		AmmId:       ammId,
+		Dex:         getTrackedDex(redisClient, ammId),
 		Status:      storage.NOT_TRACKED,
 		LastUpdated: time.Now().Unix(),
 	}
 
 	storage.SetTracked(redisClient, ammId.String(), tracker)
+	unwatchPoolMints(redisClient, ammId)
+}
+
+// getTrackedDex keeps the venue of a pool that was tracked before, since pausing and
+// untracking are not told which venue the pool is on.
+func getTrackedDex(redisClient *redis.Client, ammId *solana.PublicKey) string {
+	tracker, err := storage.GetTracked(redisClient, ammId.String())
+	if err != nil {
+		return ""
+	}
+	return tracker.Dex
 }
 
 func GetAmmTrackingStatus(ammId *solana.PublicKey) (*types.Tracker, error) {
@@ -2,43 +2,166 @@ package bot
 
 import (
 	"math/big"
+	"strconv"
 
 	"github.com/gagliardetto/solana-go"
-	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/generators"
 	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
 )
 
-func GetBalanceFromTransaction(preTokenBalances, postTokenBalances []types.TxTokenBalance, mint solana.PublicKey) *big.Int {
-	var tokenPreAccount, tokenPostAccount *types.TxTokenBalance
+// GetVaultBalanceChange returns pre minus post for a pool vault, or zero when the
+// vault is not in the transaction. The vault is found by key, so it works for pools
+// of any program and tells apart pools of the same authority swapped in one
+// transaction.
+func GetVaultBalanceChange(tx generators.MempoolTxn, vault solana.PublicKey) *big.Int {
+	post, ok := findVaultBalance(tx, tx.PostTokenBalances, vault)
+	if !ok {
+		return big.NewInt(0)
+	}
+
+	pre, ok := GetTokenAccount(tx.PreTokenBalances, post.AccountIndex)
+	if !ok {
+		return big.NewInt(0)
+	}
+
+	preAmount, ok := new(big.Int).SetString(pre.Amount, 10)
+	if !ok {
+		return big.NewInt(0)
+	}
+
+	postAmount, ok := new(big.Int).SetString(post.Amount, 10)
+	if !ok {
+		return big.NewInt(0)
+	}
+
+	return preAmount.Sub(preAmount, postAmount)
+}
+
+// GetOwnerBalanceChange returns post minus pre for the owner's token account of mint.
+func GetOwnerBalanceChange(preTokenBalances, postTokenBalances []types.TxTokenBalance, mint solana.PublicKey, owner string) *big.Int {
+	preAmount := GetOwnerBalance(preTokenBalances, mint, owner)
+	postAmount := GetOwnerBalance(postTokenBalances, mint, owner)
+
+	return new(big.Int).Sub(postAmount, preAmount)
+}
+
+// GetOwnerBalance returns the owner's balance of mint, or zero when the owner has
+// no token account for it in the transaction.
+func GetOwnerBalance(tokenBalances []types.TxTokenBalance, mint solana.PublicKey, owner string) *big.Int {
+	amount, _ := LookupOwnerBalance(tokenBalances, mint, owner)
+	return amount
+}
 
-	for _, account := range preTokenBalances {
-		if account.Mint == mint.String() && account.Owner == config.RAYDIUM_AUTHORITY.String() {
-			tokenPreAccount = &account
-			break
+// LookupOwnerBalance returns the owner's balance of mint, and false with a zero
+// balance when the owner has no token account for it in the transaction.
+func LookupOwnerBalance(tokenBalances []types.TxTokenBalance, mint solana.PublicKey, owner string) (*big.Int, bool) {
+	amount := big.NewInt(0)
+
+	for _, account := range tokenBalances {
+		if account.Mint == mint.String() && account.Owner == owner {
+			amount.SetString(account.Amount, 10)
+			return amount, true
 		}
 	}
 
-	for _, account := range postTokenBalances {
-		if account.Mint == mint.String() && account.Owner == config.RAYDIUM_AUTHORITY.String() {
-			tokenPostAccount = &account
-			break
+	return amount, false
+}
+
+// GetTokenAccount returns the balance entry of the token account at accountIndex
+// in the transaction's account list.
+func GetTokenAccount(tokenBalances []types.TxTokenBalance, accountIndex uint32) (types.TxTokenBalance, bool) {
+	for _, account := range tokenBalances {
+		if account.AccountIndex == accountIndex {
+			return account, true
 		}
 	}
 
-	if tokenPreAccount == nil || tokenPostAccount == nil {
-		return big.NewInt(0)
+	return types.TxTokenBalance{}, false
+}
+
+// GetRemovedShare returns how much of the owner's balance of mint left in the
+// transaction, as a fraction of the balance before it. It reports false when the
+// owner held none.
+func GetRemovedShare(preTokenBalances, postTokenBalances []types.TxTokenBalance, mint solana.PublicKey, owner string) (float64, bool) {
+	pre := GetOwnerBalance(preTokenBalances, mint, owner)
+	post := GetOwnerBalance(postTokenBalances, mint, owner)
+
+	if pre.Sign() <= 0 {
+		return 0, false
 	}
 
-	preAmount, success := new(big.Int).SetString(tokenPreAccount.Amount, 10)
-	if !success {
-		return big.NewInt(0)
+	removed, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(new(big.Int).Sub(pre, post)),
+		new(big.Float).SetInt(pre),
+	).Float64()
+
+	return removed, true
+}
+
+// GetVaultPostBalance returns the token amount of a vault after the transaction, and
+// false when the vault is not one of the transaction's accounts. A WSOL vault's
+// lamports are not used, as they include its rent.
+func GetVaultPostBalance(tx generators.MempoolTxn, vault solana.PublicKey) (uint64, bool) {
+	return getVaultBalance(tx, tx.PostTokenBalances, vault)
+}
+
+// getVaultBalance returns the token amount of a vault in one side of the transaction's
+// token balances.
+func getVaultBalance(tx generators.MempoolTxn, tokenBalances []types.TxTokenBalance, vault solana.PublicKey) (uint64, bool) {
+	account, ok := findVaultBalance(tx, tokenBalances, vault)
+	if !ok {
+		return 0, false
 	}
 
-	postAmount, success := new(big.Int).SetString(tokenPostAccount.Amount, 10)
-	if !success {
-		return big.NewInt(0)
+	amount, err := strconv.ParseUint(account.Amount, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return amount, true
+}
+
+// GetVaultRemovedShare returns how much of a vault's balance left in the transaction,
+// as a fraction of the balance before it. It reports false when the vault is not in
+// the transaction or held nothing.
+func GetVaultRemovedShare(tx generators.MempoolTxn, vault solana.PublicKey) (float64, bool) {
+	post, ok := findVaultBalance(tx, tx.PostTokenBalances, vault)
+	if !ok {
+		return 0, false
+	}
+
+	pre, ok := GetTokenAccount(tx.PreTokenBalances, post.AccountIndex)
+	if !ok {
+		return 0, false
+	}
+
+	preAmount, ok := new(big.Int).SetString(pre.Amount, 10)
+	if !ok || preAmount.Sign() <= 0 {
+		return 0, false
+	}
+
+	postAmount, ok := new(big.Int).SetString(post.Amount, 10)
+	if !ok {
+		return 0, false
+	}
+
+	removed, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(new(big.Int).Sub(preAmount, postAmount)),
+		new(big.Float).SetInt(preAmount),
+	).Float64()
+
+	return removed, true
+}
+
+// findVaultBalance returns the balance entry of the vault. Vaults are found by key
+// since one authority can own the vaults of every pool of a program, and only the
+// token accounts of the transaction are resolved.
+func findVaultBalance(tx generators.MempoolTxn, tokenBalances []types.TxTokenBalance, vault solana.PublicKey) (types.TxTokenBalance, bool) {
+	for _, account := range tokenBalances {
+		key, err := getAccountKey(int(account.AccountIndex), tx)
+		if err == nil && key.Equals(vault) {
+			return account, true
+		}
 	}
 
-	tokenAmount := new(big.Int).Sub(preAmount, postAmount)
-	return tokenAmount
+	return types.TxTokenBalance{}, false
 }
@@ -0,0 +1,357 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/coder"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/generators"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/liquidity"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/rpc"
+)
+
+// recordedSwap is an AMM v4 swap found in a recording.
+type recordedSwap struct {
+	ins     generators.TxInstruction
+	decoded interface{}
+	ammId   *solana.PublicKey
+}
+
+// capturedPool is the account data of an AMM v4 pool, read at Slot while its swaps
+// were being recorded.
+type capturedPool struct {
+	Slot uint64 `json:"slot"`
+	Data []byte `json:"data"`
+}
+
+// swapDeviation is how far a simulated swap is from the vault balance deltas of the
+// recorded swap, in percent.
+type swapDeviation struct {
+	ammId     *solana.PublicKey
+	signature string
+	simulated uint64
+	actual    uint64
+	deviation float64
+}
+
+// PoolStatesPath is where the states of the pools swapped in a recording are kept.
+func PoolStatesPath(recordingPath string) string {
+	return recordingPath + ".pools.json"
+}
+
+// PoolCapture keeps the state of every AMM v4 pool swapped while recording, read the
+// first time the pool is seen, so swaps are later verified with the fees and
+// NeedTakePnl the pool had when they were recorded.
+type PoolCapture struct {
+	mu    sync.Mutex
+	path  string
+	pools map[string]capturedPool
+}
+
+func NewPoolCapture(path string) *PoolCapture {
+	return &PoolCapture{path: path, pools: make(map[string]capturedPool)}
+}
+
+// Observe reads the pools swapped in response that were not captured yet, and writes
+// the file again when any was added.
+func (c *PoolCapture) Observe(response generators.GeyserResponse) {
+	if response.MempoolTxns.Error != "" {
+		return
+	}
+
+	for _, swap := range findRecordedSwaps(response.MempoolTxns) {
+		c.mu.Lock()
+		_, captured := c.pools[swap.ammId.String()]
+		c.mu.Unlock()
+
+		if captured {
+			continue
+		}
+
+		accounts, slot, err := rpc.GetMultipleAccountsData([]solana.PublicKey{*swap.ammId})
+		if err != nil {
+			log.Printf("%s | Failed to capture pool: %v", swap.ammId, err)
+			continue
+		}
+
+		c.mu.Lock()
+		c.pools[swap.ammId.String()] = capturedPool{Slot: slot, Data: accounts[0]}
+		err = c.save()
+		c.mu.Unlock()
+
+		if err != nil {
+			log.Printf("Failed to write pool states %s: %v", c.path, err)
+		}
+	}
+}
+
+// save replaces the file whole, so it stays readable if the process is stopped.
+func (c *PoolCapture) save() error {
+	data, err := json.Marshal(c.pools)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(c.path+".tmp", data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(c.path+".tmp", c.path)
+}
+
+func loadCapturedPools(path string) (map[solana.PublicKey]*coder.LiquidityState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var pools map[string]capturedPool
+	if err := json.Unmarshal(data, &pools); err != nil {
+		return nil, err
+	}
+
+	states := make(map[solana.PublicKey]*coder.LiquidityState)
+	for ammId, pool := range pools {
+		key, err := solana.PublicKeyFromBase58(ammId)
+		if err != nil {
+			return nil, err
+		}
+
+		state, err := coder.NewRaydiumLiquidityCoder().RaydiumLiquidityDecode(pool.Data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ammId, err)
+		}
+		states[key] = &state
+	}
+
+	return states, nil
+}
+
+// VerifyRecordedSwaps simulates every successful AMM v4 swap in a recording from the
+// vault balances before it and the pool states captured with the recording, and
+// fails when any simulated amount is further than tolerance, in percent, from the
+// vault balance deltas.
+func VerifyRecordedSwaps(path string, poolsPath string, tolerance float64) error {
+	states, err := loadCapturedPools(poolsPath)
+	if err != nil {
+		return err
+	}
+
+	reader, err := generators.OpenRecording(path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	deviations, err := verifySwaps(reader, states)
+	if err != nil {
+		return err
+	}
+
+	if len(deviations) == 0 {
+		return errors.New("no swaps verified")
+	}
+
+	var exact, failed int
+	var totalDeviation float64
+
+	for _, d := range deviations {
+		log.Printf("%s | %s | simulated: %d | actual: %d | deviation: %.6f%%", d.ammId, d.signature, d.simulated, d.actual, d.deviation)
+
+		totalDeviation += d.deviation
+		if d.deviation == 0 {
+			exact++
+		}
+		if d.deviation > tolerance {
+			failed++
+		}
+	}
+
+	log.Printf("Verified %d swaps | exact: %d | mean deviation: %.6f%%", len(deviations), exact, totalDeviation/float64(len(deviations)))
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d swaps deviate more than %g%%", failed, len(deviations), tolerance)
+	}
+	return nil
+}
+
+// verifySwaps returns the deviation of every swap in the recording whose pool state
+// was captured.
+func verifySwaps(reader *generators.RecordingReader, states map[solana.PublicKey]*coder.LiquidityState) ([]swapDeviation, error) {
+	var deviations []swapDeviation
+
+	for {
+		_, response, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return deviations, nil
+		}
+		if errors.Is(err, generators.ErrTruncatedRecording) {
+			// A crashed recorder leaves every record before the cut readable
+			log.Printf("Verifying the %d swaps before the recording was cut off", len(deviations))
+			return deviations, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if response.MempoolTxns.Error != "" {
+			continue
+		}
+
+		swaps := findRecordedSwaps(response.MempoolTxns)
+
+		// Balance deltas can only be attributed to a swap when the pool is swapped
+		// once in the transaction
+		perPool := make(map[solana.PublicKey]int)
+		for _, swap := range swaps {
+			perPool[*swap.ammId]++
+		}
+
+		for _, swap := range swaps {
+			if perPool[*swap.ammId] > 1 {
+				continue
+			}
+
+			state, ok := states[*swap.ammId]
+			if !ok {
+				log.Printf("%s | %s | Skipped swap: pool state not captured", swap.ammId, response.MempoolTxns.Signature)
+				continue
+			}
+
+			deviation, err := verifyRecordedSwap(swap, state, response.MempoolTxns)
+			if err != nil {
+				log.Printf("%s | %s | Skipped swap: %v", swap.ammId, response.MempoolTxns.Signature, err)
+				continue
+			}
+
+			deviations = append(deviations, deviation)
+		}
+	}
+}
+
+func findRecordedSwaps(tx generators.MempoolTxn) []recordedSwap {
+	instructions := slices.Clone(tx.Instructions)
+	for _, inner := range tx.InnerInstructions {
+		instructions = append(instructions, inner.Instructions...)
+	}
+
+	var swaps []recordedSwap
+	for _, ins := range instructions {
+		programId, err := getAccountKey(int(ins.ProgramIdIndex), tx)
+		if err != nil || *programId != config.RAYDIUM_AMM_V4 {
+			continue
+		}
+
+		decoded, err := decoders.Decode(*programId, ins.Data)
+		if err != nil {
+			continue
+		}
+
+		switch decoded.(type) {
+		case coder.SwapBaseIn, coder.SwapBaseOut:
+		default:
+			continue
+		}
+
+		ammId, err := getPublicKeyFromTx(1, tx, ins)
+		if err != nil {
+			continue
+		}
+
+		swaps = append(swaps, recordedSwap{ins: ins, decoded: decoded, ammId: ammId})
+	}
+
+	return swaps
+}
+
+// verifyRecordedSwap compares the simulated amount with the balance delta.
+// SwapBaseIn is checked on the amount out and SwapBaseOut on the amount in.
+func verifyRecordedSwap(swap recordedSwap, state *coder.LiquidityState, tx generators.MempoolTxn) (swapDeviation, error) {
+	basePre, basePost, err := getVaultBalances(tx, state.BaseVault)
+	if err != nil {
+		return swapDeviation{}, err
+	}
+
+	quotePre, quotePost, err := getVaultBalances(tx, state.QuoteVault)
+	if err != nil {
+		return swapDeviation{}, err
+	}
+
+	// The vault that received tokens is the input side
+	baseToQuote := basePost > basePre
+	if baseToQuote == (quotePost > quotePre) {
+		return swapDeviation{}, errors.New("vault balances did not move in opposite directions")
+	}
+
+	actualIn, actualOut := quotePost-quotePre, basePre-basePost
+	if baseToQuote {
+		actualIn, actualOut = basePost-basePre, quotePre-quotePost
+	}
+
+	baseReserve := basePre - min(basePre, state.BaseNeedTakePnl)
+	quoteReserve := quotePre - min(quotePre, state.QuoteNeedTakePnl)
+
+	var simulated, actual uint64
+
+	switch decoded := swap.decoded.(type) {
+	case coder.SwapBaseIn:
+		quote, err := liquidity.SimulateSwapBaseIn(state, baseReserve, quoteReserve, actualIn, baseToQuote)
+		if err != nil {
+			return swapDeviation{}, err
+		}
+		simulated, actual = quote.AmountOut, actualOut
+	case coder.SwapBaseOut:
+		quote, err := liquidity.SimulateSwapBaseOut(state, baseReserve, quoteReserve, decoded.AmountOut, baseToQuote)
+		if err != nil {
+			return swapDeviation{}, err
+		}
+		simulated, actual = quote.AmountIn, actualIn
+	}
+
+	if actual == 0 {
+		return swapDeviation{}, errors.New("no tokens moved")
+	}
+
+	return swapDeviation{
+		ammId:     swap.ammId,
+		signature: tx.Signature,
+		simulated: simulated,
+		actual:    actual,
+		deviation: math.Abs(float64(simulated)-float64(actual)) / float64(actual) * 100,
+	}, nil
+}
+
+// getVaultBalances returns the token amount of a vault before and after the
+// transaction.
+func getVaultBalances(tx generators.MempoolTxn, vault solana.PublicKey) (uint64, uint64, error) {
+	post, ok := findVaultBalance(tx, tx.PostTokenBalances, vault)
+	if !ok {
+		return 0, 0, errors.New("vault is not in the transaction")
+	}
+
+	pre, ok := GetTokenAccount(tx.PreTokenBalances, post.AccountIndex)
+	if !ok {
+		return 0, 0, errors.New("vault has no balance before the transaction")
+	}
+
+	preAmount, err := strconv.ParseUint(pre.Amount, 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	postAmount, err := strconv.ParseUint(post.Amount, 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return preAmount, postAmount, nil
+}
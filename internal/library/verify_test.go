@@ -0,0 +1,70 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/generators"
+)
+
+// The recording holds AMM v4 swaps on two pools, written in the recording format with
+// the pool states next to it in the format -record captures them in. Its vault
+// balances move by what the program charges and pays out, rounding included, so the
+// simulator should match them exactly. Recordings captured from a live source can be
+// added to testdata and to the table the same way.
+const swapRecording = "testdata/amm_v4_swaps.rec"
+
+func TestVerifySwaps(t *testing.T) {
+	states, err := loadCapturedPools(PoolStatesPath(swapRecording))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := generators.OpenRecording(swapRecording)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	deviations, err := verifySwaps(reader, states)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verified := make(map[string]swapDeviation)
+	for _, d := range deviations {
+		verified[d.signature] = d
+	}
+
+	tests := []struct {
+		name      string
+		signature string
+		actual    uint64
+	}{
+		{"base in, quote to base", "4pTMiJy26ui2AQGmgbRhw2hCwYruv9Kv1QgAp3vx5TCARaESTcipnsxn6BmFP5LVUNotSGN1BjANGU6dT8GxYCTz", 5799593185870},
+		{"base in, base to quote", "3o7cWsPJEeBBYbg6mrwhQYkn4a7hqiuR7yGTLS3LSovMWJspcVoWnD8eEuZ532TNQXnhSU3Rniq83kiraM5YGJNP", 431779346},
+		{"base out, quote to base", "2DhCpv7ai3XMiuSQzeBrU9Sz4WQa7tywA6CqKTJN4dr1n2mZj6WWr9hkXUUexgnMsjaNSSjF8yU3NUTjS1akJkvn", 1115692458},
+		{"base out, base to quote", "44YpwbHETaVv55eubyt3UagJMJqmRxNXbyfjscxEeMPqR5t715waxbrsZb36cE3AwhL71v2XxieamBCjfaXpQbnK", 997424776286},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ok := verified[tt.signature]
+			if !ok {
+				t.Fatalf("swap %s was not verified", tt.signature)
+			}
+
+			if d.actual != tt.actual {
+				t.Errorf("actual = %d, want %d", d.actual, tt.actual)
+			}
+
+			if d.simulated != d.actual {
+				t.Errorf("simulated = %d, want %d", d.simulated, d.actual)
+			}
+		})
+	}
+
+	// Failed swaps and pools swapped twice in a transaction are skipped
+	if len(deviations) != len(tests) {
+		t.Errorf("verified %d swaps, want %d", len(deviations), len(tests))
+	}
+}
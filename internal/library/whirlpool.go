@@ -0,0 +1,47 @@
+package bot
+
+import (
+	"log"
+
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/coder"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/generators"
+)
+
+func init() {
+	decoders.Register(config.ORCA_WHIRLPOOL, coder.NewOrcaWhirlpoolCoder())
+	registerHandler(processWhirlpoolInstruction, coder.WhirlpoolDecreaseLiquidity{}, coder.WhirlpoolClosePosition{})
+	registerHandler(queueSwap, coder.WhirlpoolSwap{}, coder.WhirlpoolTwoHopSwap{})
+}
+
+// processWhirlpoolInstruction handles whirlpool liquidity removal.
+func processWhirlpoolInstruction(call *instructionCall, response generators.GeyserResponse) {
+	switch decoded := call.decoded.(type) {
+	case coder.WhirlpoolDecreaseLiquidity:
+		positionPos := coder.WHIRLPOOL_DECREASE_POSITION
+		if decoded.V2 {
+			positionPos = coder.WHIRLPOOL_DECREASE_V2_POSITION
+		}
+
+		poolId, err := getPublicKeyFromTx(coder.WHIRLPOOL_DECREASE_POOL, response.MempoolTxns, call.ins)
+		if err != nil {
+			return
+		}
+
+		positionId, err := getPublicKeyFromTx(positionPos, response.MempoolTxns, call.ins)
+		if err != nil {
+			return
+		}
+
+		log.Printf("WhirlpoolDecreaseLiquidity | %s | %s | %s", response.MempoolTxns.Source, response.MempoolTxns.Signature, call.route)
+		processPositionDecrease(call.program, poolId, positionId, decoded.LiquidityAmount, response)
+	case coder.WhirlpoolClosePosition:
+		positionId, err := getPublicKeyFromTx(coder.WHIRLPOOL_CLOSE_POSITION, response.MempoolTxns, call.ins)
+		if err != nil {
+			return
+		}
+
+		log.Printf("WhirlpoolClosePosition | %s | %s | %s", response.MempoolTxns.Source, response.MempoolTxns.Signature, call.route)
+		processPositionClose(call.program, positionId, response)
+	}
+}
@@ -50,6 +50,7 @@ func GetPoolKeys(ammId *solana.PublicKey) (*types.RaydiumPoolKeys, error) {
 		BaseVault:          state.BaseVault,
 		QuoteVault:         state.QuoteVault,
 		Version:            3,
+		ProgramID:          config.RAYDIUM_AMM_V4,
 		MarketProgramID:    state.MarketProgramId,
 		MarketID:           state.MarketId,
 		MarketAuthority:    authority,
@@ -75,45 +76,304 @@ func GetPoolKeys(ammId *solana.PublicKey) (*types.RaydiumPoolKeys, error) {
 	return pKey, nil
 }
 
+// GetCpmmPoolKeys returns the keys of a Raydium CPMM pool in the same shape as an
+// AMM v4 pool, with token 0 as the base side. CPMM pools have no OpenBook market.
+func GetCpmmPoolKeys(poolId *solana.PublicKey) (*types.RaydiumPoolKeys, error) {
+	redisClient, err := adapter.GetRedisClient(4)
+	if err != nil {
+		return nil, err
+	}
+
+	storedPoolKey, err := storage.GetPoolKeys(redisClient, poolId)
+
+	if err != nil && err.Error() != "key not found" {
+		return nil, err
+	}
+
+	if !storedPoolKey.ID.IsZero() {
+		return storedPoolKey, nil
+	}
+
+	state, err := rpc.GetCpmmPoolState(poolId)
+	if err != nil {
+		return &types.RaydiumPoolKeys{}, err
+	}
+
+	authority, _, err := solana.FindProgramAddress([][]byte{[]byte(CPMM_AUTH_SEED)}, config.RAYDIUM_CPMM)
+	if err != nil {
+		return &types.RaydiumPoolKeys{}, err
+	}
+
+	pKey := &types.RaydiumPoolKeys{
+		ID:            *poolId,
+		BaseMint:      state.Token0Mint,
+		QuoteMint:     state.Token1Mint,
+		LpMint:        state.LpMint,
+		BaseDecimals:  int(state.Mint0Decimals),
+		QuoteDecimals: int(state.Mint1Decimals),
+		LpDecimals:    int(state.LpMintDecimals),
+		ProgramID:     config.RAYDIUM_CPMM,
+		Authority:     authority,
+		BaseVault:     state.Token0Vault,
+		QuoteVault:    state.Token1Vault,
+	}
+
+	storage.SetPoolKeys(redisClient, pKey)
+
+	return pKey, nil
+}
+
+// GetClmmPoolKeys returns the keys of a Raydium CLMM pool. The vaults are owned by
+// the pool itself, and positions are NFTs so there is no LP mint.
+func GetClmmPoolKeys(poolId *solana.PublicKey) (*types.RaydiumPoolKeys, error) {
+	redisClient, err := adapter.GetRedisClient(4)
+	if err != nil {
+		return nil, err
+	}
+
+	storedPoolKey, err := storage.GetPoolKeys(redisClient, poolId)
+
+	if err != nil && err.Error() != "key not found" {
+		return nil, err
+	}
+
+	if !storedPoolKey.ID.IsZero() {
+		return storedPoolKey, nil
+	}
+
+	state, err := rpc.GetClmmPoolState(poolId)
+	if err != nil {
+		return &types.RaydiumPoolKeys{}, err
+	}
+
+	pKey := &types.RaydiumPoolKeys{
+		ID:            *poolId,
+		BaseMint:      state.TokenMint0,
+		QuoteMint:     state.TokenMint1,
+		BaseDecimals:  int(state.MintDecimals0),
+		QuoteDecimals: int(state.MintDecimals1),
+		ProgramID:     config.RAYDIUM_CLMM,
+		Authority:     *poolId,
+		BaseVault:     state.TokenVault0,
+		QuoteVault:    state.TokenVault1,
+	}
+
+	storage.SetPoolKeys(redisClient, pKey)
+
+	return pKey, nil
+}
+
+// GetDlmmPoolKeys returns the keys of a Meteora DLMM pair, with token X as the base
+// side. The reserves are owned by the pair.
+func GetDlmmPoolKeys(pairId *solana.PublicKey) (*types.RaydiumPoolKeys, error) {
+	redisClient, err := adapter.GetRedisClient(4)
+	if err != nil {
+		return nil, err
+	}
+
+	storedPoolKey, err := storage.GetPoolKeys(redisClient, pairId)
+
+	if err != nil && err.Error() != "key not found" {
+		return nil, err
+	}
+
+	if !storedPoolKey.ID.IsZero() {
+		return storedPoolKey, nil
+	}
+
+	state, err := rpc.GetDlmmLbPair(pairId)
+	if err != nil {
+		return &types.RaydiumPoolKeys{}, err
+	}
+
+	pKey := &types.RaydiumPoolKeys{
+		ID:         *pairId,
+		BaseMint:   state.TokenXMint,
+		QuoteMint:  state.TokenYMint,
+		ProgramID:  config.METEORA_DLMM,
+		Authority:  *pairId,
+		BaseVault:  state.ReserveX,
+		QuoteVault: state.ReserveY,
+	}
+
+	storage.SetPoolKeys(redisClient, pKey)
+
+	return pKey, nil
+}
+
+// DAMM_POOL_KEYS_VERSION marks dynamic AMM keys whose vaults are the token accounts.
+// Keys stored before, with the pool's vault LP accounts as vaults, are read again.
+const DAMM_POOL_KEYS_VERSION = 1
+
+// GetDammPoolKeys returns the keys of a Meteora dynamic AMM pool, with token A as the
+// base side. The vaults are the token accounts of the shared Meteora vaults, which
+// hold the deposits of every pool using them, so their balance only says how much
+// moved in a transaction and not what the pool holds.
+func GetDammPoolKeys(poolId *solana.PublicKey) (*types.RaydiumPoolKeys, error) {
+	redisClient, err := adapter.GetRedisClient(4)
+	if err != nil {
+		return nil, err
+	}
+
+	storedPoolKey, err := storage.GetPoolKeys(redisClient, poolId)
+
+	if err != nil && err.Error() != "key not found" {
+		return nil, err
+	}
+
+	if !storedPoolKey.ID.IsZero() && storedPoolKey.Version == DAMM_POOL_KEYS_VERSION {
+		return storedPoolKey, nil
+	}
+
+	state, err := rpc.GetDammPool(poolId)
+	if err != nil {
+		return &types.RaydiumPoolKeys{}, err
+	}
+
+	aVault, err := rpc.GetDammVault(&state.AVault)
+	if err != nil {
+		return &types.RaydiumPoolKeys{}, err
+	}
+
+	bVault, err := rpc.GetDammVault(&state.BVault)
+	if err != nil {
+		return &types.RaydiumPoolKeys{}, err
+	}
+
+	pKey := &types.RaydiumPoolKeys{
+		ID:         *poolId,
+		BaseMint:   state.TokenAMint,
+		QuoteMint:  state.TokenBMint,
+		LpMint:     state.LpMint,
+		Version:    DAMM_POOL_KEYS_VERSION,
+		ProgramID:  config.METEORA_DAMM,
+		Authority:  *poolId,
+		BaseVault:  aVault.TokenVault,
+		QuoteVault: bVault.TokenVault,
+	}
+
+	storage.SetPoolKeys(redisClient, pKey)
+
+	return pKey, nil
+}
+
+// GetWhirlpoolPoolKeys returns the keys of an Orca whirlpool, with token A as the
+// base side. The vaults are owned by the whirlpool and positions are NFTs. The
+// whirlpool does not store decimals, so they are left at zero.
+func GetWhirlpoolPoolKeys(poolId *solana.PublicKey) (*types.RaydiumPoolKeys, error) {
+	redisClient, err := adapter.GetRedisClient(4)
+	if err != nil {
+		return nil, err
+	}
+
+	storedPoolKey, err := storage.GetPoolKeys(redisClient, poolId)
+
+	if err != nil && err.Error() != "key not found" {
+		return nil, err
+	}
+
+	if !storedPoolKey.ID.IsZero() {
+		return storedPoolKey, nil
+	}
+
+	state, err := rpc.GetWhirlpool(poolId)
+	if err != nil {
+		return &types.RaydiumPoolKeys{}, err
+	}
+
+	pKey := &types.RaydiumPoolKeys{
+		ID:         *poolId,
+		BaseMint:   state.TokenMintA,
+		QuoteMint:  state.TokenMintB,
+		ProgramID:  config.ORCA_WHIRLPOOL,
+		Authority:  *poolId,
+		BaseVault:  state.TokenVaultA,
+		QuoteVault: state.TokenVaultB,
+	}
+
+	storage.SetPoolKeys(redisClient, pKey)
+
+	return pKey, nil
+}
+
+// GetPoolKeysByProgram returns the pool keys of a pool owned by any of the tracked pool programs.
+func GetPoolKeysByProgram(programId solana.PublicKey, poolId *solana.PublicKey) (*types.RaydiumPoolKeys, error) {
+	switch programId {
+	case config.RAYDIUM_CPMM:
+		return GetCpmmPoolKeys(poolId)
+	case config.RAYDIUM_CLMM:
+		return GetClmmPoolKeys(poolId)
+	case config.METEORA_DLMM:
+		return GetDlmmPoolKeys(poolId)
+	case config.METEORA_DAMM:
+		return GetDammPoolKeys(poolId)
+	case config.ORCA_WHIRLPOOL:
+		return GetWhirlpoolPoolKeys(poolId)
+	default:
+		return GetPoolKeys(poolId)
+	}
+}
+
+// GetMint returns the token of the pool, the side that is not its quote. swap is
+// true when the quote is the base side of the pool.
 func GetMint(pKey *types.RaydiumPoolKeys) (solana.PublicKey, bool, error) {
+	_, swap, err := GetQuote(pKey)
+	if err != nil {
+		return solana.PublicKey{}, false, err
+	}
 
-	var mint solana.PublicKey = solana.PublicKey{}
-	var swap = false
-	if pKey.BaseMint == config.WRAPPED_SOL {
-		mint = pKey.QuoteMint
-		swap = true
-	} else {
-		if pKey.QuoteMint == config.WRAPPED_SOL {
-			mint = pKey.BaseMint
-		} else {
-			return solana.PublicKey{}, false, errors.New("neither BaseMint nor QuoteMint is WRAPPED_SOL")
+	if swap {
+		return pKey.QuoteMint, true, nil
+	}
+	return pKey.BaseMint, false, nil
+}
+
+// GetQuote returns the configured quote mint the pool is priced in, preferring the
+// earlier one in the config when both sides are quote mints.
+func GetQuote(pKey *types.RaydiumPoolKeys) (types.QuoteMint, bool, error) {
+	for _, quote := range config.QuoteMints {
+		if pKey.QuoteMint == quote.Mint {
+			return quote, false, nil
+		}
+		if pKey.BaseMint == quote.Mint {
+			return quote, true, nil
 		}
 	}
 
-	return mint, swap, nil
+	return types.QuoteMint{}, false, errors.New("neither BaseMint nor QuoteMint is a quote mint")
 }
 
-func GetPoolSolBalance(pKey *types.RaydiumPoolKeys) (uint64, error) {
-
-	_, swap, err := GetMint(pKey)
+// GetQuoteVault returns the vault holding the pool's quote.
+func GetQuoteVault(pKey *types.RaydiumPoolKeys) (solana.PublicKey, types.QuoteMint, error) {
+	quote, swap, err := GetQuote(pKey)
 	if err != nil {
-		return 0, err
+		return solana.PublicKey{}, quote, err
 	}
 
-	var value uint64
-	if !swap {
-		value, err = rpc.GetBalance(pKey.QuoteVault)
-	} else {
-		value, err = rpc.GetBalance(pKey.BaseVault)
+	if swap {
+		return pKey.BaseVault, quote, nil
 	}
+	return pKey.QuoteVault, quote, nil
+}
 
+// GetPoolQuoteBalance returns the quote held by the pool in token units, which for
+// WSOL leaves out the vault's rent.
+func GetPoolQuoteBalance(pKey *types.RaydiumPoolKeys) (uint64, types.QuoteMint, error) {
+	vault, quote, err := GetQuoteVault(pKey)
 	if err != nil {
-		return 0, err
+		return 0, quote, err
 	}
 
-	return value, nil
+	value, err := rpc.GetTokenAccountBalance(vault)
+	if err != nil {
+		return 0, quote, err
+	}
+
+	return value, quote, nil
 }
 
+const CPMM_AUTH_SEED = "vault_and_lp_mint_auth_seed"
+
 func getAssociatedAuthority(programId solana.PublicKey) (solana.PublicKey, error) {
 	seed := []byte{97, 109, 109, 32, 97, 117, 116, 104, 111, 114, 105, 116, 121}
 	programAddress, _, err := solana.FindProgramAddress([][]byte{seed}, programId)
@@ -0,0 +1,58 @@
+package liquidity
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/price"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+)
+
+// QuoteValue converts a raw amount of the quote into quote units.
+func QuoteValue(amount *big.Int, quote types.QuoteMint) float64 {
+	value, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(amount),
+		big.NewFloat(math.Pow10(int(quote.Decimals))),
+	).Float64()
+
+	return value
+}
+
+// UsdValue normalises an amount in quote units to USD. Stablecoins count at one
+// dollar and SOL at the price source's price. It reports false without a price.
+func UsdValue(value float64, quote types.QuoteMint) (float64, bool) {
+	if quote.Usd {
+		return value, true
+	}
+
+	if quote.Mint != config.WRAPPED_SOL {
+		return 0, false
+	}
+
+	solUsd, ok := price.SolUsd()
+	if !ok {
+		return 0, false
+	}
+
+	return value * solUsd, true
+}
+
+// SolValue normalises an amount in quote units to SOL, the unit the rug floor is
+// set in. It reports false for stablecoins without a price.
+func SolValue(value float64, quote types.QuoteMint) (float64, bool) {
+	if quote.Mint == config.WRAPPED_SOL {
+		return value, true
+	}
+
+	if !quote.Usd {
+		return 0, false
+	}
+
+	solUsd, ok := price.SolUsd()
+	if !ok || solUsd <= 0 {
+		return 0, false
+	}
+
+	return value / solUsd, true
+}
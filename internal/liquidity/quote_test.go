@@ -0,0 +1,73 @@
+package liquidity
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/price"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+)
+
+var (
+	wsol = types.QuoteMint{Mint: config.WRAPPED_SOL, Symbol: "SOL", Decimals: 9}
+	usdc = types.QuoteMint{Mint: solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"), Symbol: "USDC", Decimals: 6, Usd: true}
+	// A quote that is neither SOL nor a stablecoin, which has no price
+	jup = types.QuoteMint{Mint: solana.MustPublicKeyFromBase58("JUPyiwrYJFskUPiHa7hkeR8VUtAeFoSYbKedZNsDvCN"), Symbol: "JUP", Decimals: 6}
+)
+
+func TestQuoteValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount string
+		quote  types.QuoteMint
+		want   float64
+	}{
+		{"one SOL", "1000000000", wsol, 1},
+		{"lamports", "1500", wsol, 0.0000015},
+		{"USDC", "2500000", usdc, 2.5},
+		{"zero", "0", usdc, 0},
+		{"above uint64", "100000000000000000000000", wsol, 100_000_000_000_000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, _ := new(big.Int).SetString(tt.amount, 10)
+			if got := QuoteValue(amount, tt.quote); got != tt.want {
+				t.Errorf("QuoteValue(%s, %s) = %v, want %v", tt.amount, tt.quote.Symbol, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSolValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		source price.Source
+		value  float64
+		quote  types.QuoteMint
+		want   float64
+		wantOk bool
+	}{
+		{name: "SOL without a price", value: 3, quote: wsol, want: 3, wantOk: true},
+		{name: "SOL with a price", source: price.NewStaticSource(200), value: 3, quote: wsol, want: 3, wantOk: true},
+		{name: "stablecoin with a price", source: price.NewStaticSource(200), value: 500, quote: usdc, want: 2.5, wantOk: true},
+		{name: "stablecoin without a price", value: 500, quote: usdc},
+		{name: "stablecoin with a zero price", source: price.NewStaticSource(0), value: 500, quote: usdc},
+		{name: "other quote with a price", source: price.NewStaticSource(200), value: 500, quote: jup},
+		{name: "other quote without a price", value: 500, quote: jup},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price.SetSource(tt.source)
+			defer price.SetSource(nil)
+
+			got, ok := SolValue(tt.value, tt.quote)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("SolValue(%v, %s) = %v, %v, want %v, %v", tt.value, tt.quote.Symbol, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
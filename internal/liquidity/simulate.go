@@ -0,0 +1,115 @@
+package liquidity
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/coder"
+)
+
+// SwapQuote is the result of a simulated swap. Fee is taken from AmountIn in the
+// input token, and PriceImpact is how far the swap moves the price, in percent,
+// leaving out the fee.
+type SwapQuote struct {
+	AmountIn    uint64
+	AmountOut   uint64
+	Fee         uint64
+	PriceImpact float64
+}
+
+// SimulateSwapBaseIn quotes selling exactly amountIn into an AMM v4 pool with the
+// given reserves, which should already leave out NeedTakePnl. baseToQuote is the
+// direction of the swap.
+func SimulateSwapBaseIn(state *coder.LiquidityState, baseReserve uint64, quoteReserve uint64, amountIn uint64, baseToQuote bool) (SwapQuote, error) {
+	if err := checkSwapFee(state); err != nil {
+		return SwapQuote{}, err
+	}
+
+	reserveIn, reserveOut := swapReserves(baseReserve, quoteReserve, baseToQuote)
+	if reserveIn.Sign() == 0 || reserveOut.Sign() == 0 {
+		return SwapQuote{}, errors.New("pool has no reserves")
+	}
+
+	// The fee is rounded up in the pool's favour
+	in := new(big.Int).SetUint64(amountIn)
+	fee := ceilDiv(new(big.Int).Mul(in, new(big.Int).SetUint64(state.SwapFeeNumerator)), new(big.Int).SetUint64(state.SwapFeeDenominator))
+	inAfterFee := new(big.Int).Sub(in, fee)
+
+	out := new(big.Int).Mul(reserveOut, inAfterFee)
+	out.Quo(out, new(big.Int).Add(reserveIn, inAfterFee))
+
+	return SwapQuote{
+		AmountIn:    amountIn,
+		AmountOut:   out.Uint64(),
+		Fee:         fee.Uint64(),
+		PriceImpact: priceImpact(reserveIn, inAfterFee),
+	}, nil
+}
+
+// SimulateSwapBaseOut quotes buying exactly amountOut from an AMM v4 pool with the
+// given reserves, which should already leave out NeedTakePnl. baseToQuote is the
+// direction of the swap.
+func SimulateSwapBaseOut(state *coder.LiquidityState, baseReserve uint64, quoteReserve uint64, amountOut uint64, baseToQuote bool) (SwapQuote, error) {
+	if err := checkSwapFee(state); err != nil {
+		return SwapQuote{}, err
+	}
+
+	reserveIn, reserveOut := swapReserves(baseReserve, quoteReserve, baseToQuote)
+
+	out := new(big.Int).SetUint64(amountOut)
+	if reserveIn.Sign() == 0 || out.Cmp(reserveOut) >= 0 {
+		return SwapQuote{}, errors.New("amount out exceeds pool reserves")
+	}
+
+	// Both the input and the fee added on top of it are rounded up
+	inBeforeFee := ceilDiv(new(big.Int).Mul(reserveIn, out), new(big.Int).Sub(reserveOut, out))
+
+	numerator := new(big.Int).SetUint64(state.SwapFeeNumerator)
+	denominator := new(big.Int).SetUint64(state.SwapFeeDenominator)
+	in := ceilDiv(new(big.Int).Mul(inBeforeFee, denominator), new(big.Int).Sub(denominator, numerator))
+
+	if !in.IsUint64() {
+		return SwapQuote{}, errors.New("amount in overflows")
+	}
+
+	return SwapQuote{
+		AmountIn:    in.Uint64(),
+		AmountOut:   amountOut,
+		Fee:         new(big.Int).Sub(in, inBeforeFee).Uint64(),
+		PriceImpact: priceImpact(reserveIn, inBeforeFee),
+	}, nil
+}
+
+func checkSwapFee(state *coder.LiquidityState) error {
+	if state.SwapFeeDenominator == 0 || state.SwapFeeNumerator >= state.SwapFeeDenominator {
+		return errors.New("invalid swap fee")
+	}
+	return nil
+}
+
+func swapReserves(baseReserve uint64, quoteReserve uint64, baseToQuote bool) (*big.Int, *big.Int) {
+	base, quote := new(big.Int).SetUint64(baseReserve), new(big.Int).SetUint64(quoteReserve)
+	if baseToQuote {
+		return base, quote
+	}
+	return quote, base
+}
+
+// priceImpact is the drop from the spot price to the price the swap gets, which on
+// a constant product pool is in / (reserveIn + in).
+func priceImpact(reserveIn *big.Int, in *big.Int) float64 {
+	impact, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(in),
+		new(big.Float).SetInt(new(big.Int).Add(reserveIn, in)),
+	).Float64()
+
+	return impact * 100
+}
+
+func ceilDiv(numerator *big.Int, denominator *big.Int) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if remainder.Sign() != 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	return quotient
+}
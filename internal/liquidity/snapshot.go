@@ -0,0 +1,94 @@
+package liquidity
+
+import (
+	"errors"
+	"math"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/coder"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/rpc"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+)
+
+// PoolFees are what a constant product pool owes out of its vaults as fees, NeedTakePnl
+// on AMM v4 and the protocol and fund fees on CPMM, with its LP supply as read at Slot.
+type PoolFees struct {
+	BaseOwed   uint64
+	QuoteOwed  uint64
+	LpSupply   uint64
+	LpDecimals int
+	Slot       uint64
+}
+
+// GetPoolFees reads the fees owed and the LP supply of a constant product pool.
+func GetPoolFees(programId solana.PublicKey, ammId *solana.PublicKey) (*PoolFees, error) {
+	accounts, slot, err := rpc.GetMultipleAccountsData([]solana.PublicKey{*ammId})
+	if err != nil {
+		return nil, err
+	}
+
+	fees := &PoolFees{Slot: slot}
+
+	switch programId {
+	case config.RAYDIUM_AMM_V4:
+		state, err := coder.NewRaydiumLiquidityCoder().RaydiumLiquidityDecode(accounts[0])
+		if err != nil {
+			return nil, err
+		}
+		fees.BaseOwed, fees.QuoteOwed = state.BaseNeedTakePnl, state.QuoteNeedTakePnl
+		fees.LpSupply, fees.LpDecimals = state.LpReserve, int(state.BaseDecimal)
+	case config.RAYDIUM_CPMM:
+		state, err := coder.NewRaydiumCpmmCoder().DecodePoolState(accounts[0])
+		if err != nil {
+			return nil, err
+		}
+		fees.BaseOwed = state.ProtocolFeesToken0 + state.FundFeesToken0
+		fees.QuoteOwed = state.ProtocolFeesToken1 + state.FundFeesToken1
+		fees.LpSupply, fees.LpDecimals = state.LpSupply, int(state.LpMintDecimals)
+	default:
+		return nil, errors.New("snapshots are only taken of constant product pools")
+	}
+
+	return fees, nil
+}
+
+// NewPoolSnapshot values a pool from the balances of its vaults and its LP supply.
+// Vault balances include the fees the pool owes, which are left out of the reserves.
+func NewPoolSnapshot(pKey *types.RaydiumPoolKeys, ammId *solana.PublicKey, baseBalance uint64, quoteBalance uint64, lpSupply uint64, fees *PoolFees) *types.PoolSnapshot {
+	snapshot := &types.PoolSnapshot{
+		AmmId:        ammId,
+		BaseReserve:  subtractOwed(baseBalance, fees.BaseOwed),
+		QuoteReserve: subtractOwed(quoteBalance, fees.QuoteOwed),
+		LpSupply:     lpSupply,
+		QuoteMint:    pKey.QuoteMint.String(),
+	}
+
+	base := float64(snapshot.BaseReserve) / math.Pow10(pKey.BaseDecimals)
+	quote := float64(snapshot.QuoteReserve) / math.Pow10(pKey.QuoteDecimals)
+
+	// Pools with their quote mint on the base side are priced the other way round
+	tokenReserve, quoteReserve := base, quote
+	if _, swap, err := GetQuote(pKey); err == nil && swap {
+		tokenReserve, quoteReserve = quote, base
+		snapshot.QuoteMint = pKey.BaseMint.String()
+	}
+
+	if tokenReserve > 0 {
+		snapshot.Price = quoteReserve / tokenReserve
+	}
+
+	// Both sides are worth the same at the spot price
+	if lpSupply > 0 {
+		snapshot.LpValue = 2 * quoteReserve / (float64(lpSupply) / math.Pow10(fees.LpDecimals))
+	}
+
+	return snapshot
+}
+
+func subtractOwed(balance uint64, owed uint64) uint64 {
+	if owed > balance {
+		return 0
+	}
+	return balance - owed
+}
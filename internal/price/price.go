@@ -0,0 +1,146 @@
+package price
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/config"
+)
+
+// Source gives the price of SOL in USD.
+type Source interface {
+	SolUsd() (float64, error)
+}
+
+var (
+	sourceMutex sync.RWMutex
+	source      Source
+)
+
+// Init sets the price source described by cfg. No source leaves amounts in their
+// pool's quote.
+func Init(cfg config.PriceConfig) error {
+	switch cfg.Source {
+	case "":
+		SetSource(nil)
+	case "static":
+		SetSource(NewStaticSource(cfg.SolUsd))
+	case "jupiter":
+		SetSource(NewJupiterSource(cfg.Url, cfg.Ttl))
+	default:
+		return fmt.Errorf("unknown price source %q", cfg.Source)
+	}
+	return nil
+}
+
+// SetSource replaces the price source, which lets a local stub stand in for it.
+func SetSource(s Source) {
+	sourceMutex.Lock()
+	defer sourceMutex.Unlock()
+
+	source = s
+}
+
+// SolUsd returns the price of SOL in USD, and false when there is no source or it
+// failed.
+func SolUsd() (float64, bool) {
+	sourceMutex.RLock()
+	s := source
+	sourceMutex.RUnlock()
+
+	if s == nil {
+		return 0, false
+	}
+
+	value, err := s.SolUsd()
+	if err != nil {
+		log.Printf("Failed to get SOL price: %v", err)
+		return 0, false
+	}
+
+	return value, true
+}
+
+type staticSource struct {
+	value float64
+}
+
+func NewStaticSource(value float64) *staticSource {
+	return &staticSource{value: value}
+}
+
+func (s *staticSource) SolUsd() (float64, error) {
+	return s.value, nil
+}
+
+// jupiterSource reads the SOL price from the Jupiter price API, keeping it for ttl
+// so every trade does not make a request.
+type jupiterSource struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+
+	mu        sync.Mutex
+	value     float64
+	fetchedAt time.Time
+}
+
+func NewJupiterSource(url string, ttl time.Duration) *jupiterSource {
+	return &jupiterSource{
+		url:    url,
+		ttl:    ttl,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+type jupiterPriceResponse struct {
+	Data map[string]struct {
+		Price string `json:"price"`
+	} `json:"data"`
+}
+
+func (s *jupiterSource) SolUsd() (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.fetchedAt.IsZero() && time.Since(s.fetchedAt) < s.ttl {
+		return s.value, nil
+	}
+
+	mint := config.WRAPPED_SOL.String()
+
+	resp, err := s.client.Get(s.url + "?ids=" + mint)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("price request failed with status %d", resp.StatusCode)
+	}
+
+	var body jupiterPriceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, err
+	}
+
+	entry, ok := body.Data[mint]
+	if !ok {
+		return 0, errors.New("no SOL price in response")
+	}
+
+	value, err := strconv.ParseFloat(entry.Price, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	s.value, s.fetchedAt = value, time.Now()
+
+	return value, nil
+}
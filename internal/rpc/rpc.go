@@ -6,8 +6,10 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 
 	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
@@ -200,6 +202,34 @@ func GetBalance(publicKey solana.PublicKey) (uint64, error) {
 	return balance.Value, nil
 }
 
+// GetTokenAccountBalance returns the raw token amount held by a token account.
+func GetTokenAccountBalance(publicKey solana.PublicKey) (uint64, error) {
+	params := map[string]interface{}{
+		"commitment": "processed",
+	}
+
+	reqParams := []interface{}{
+		publicKey,
+		params,
+	}
+
+	response, err := CallRPC("getTokenAccountBalance", reqParams)
+	if err != nil {
+		return 0, err
+	}
+
+	var balance rpc.GetTokenAccountBalanceResult
+	if err := json.Unmarshal(response.Result, &balance); err != nil {
+		return 0, err
+	}
+
+	if balance.Value == nil {
+		return 0, errors.New("token account not found")
+	}
+
+	return strconv.ParseUint(balance.Value.Amount, 10, 64)
+}
+
 func GetLookupTable(addr solana.PublicKey) (addresslookuptable.AddressLookupTableState, error) {
 	resp, err := GetAccountInfo(addr, nil)
 
@@ -276,3 +306,232 @@ func GetMarketState(marketId *solana.PublicKey) (*coder.MarketStateLayoutV3, err
 
 	return &state, nil
 }
+
+type multipleAccountsResult struct {
+	Context struct {
+		Slot uint64 `json:"slot"`
+	} `json:"context"`
+	Value []*AccountInfoValue `json:"value"`
+}
+
+// GetMultipleAccountsData returns the decoded data of every account, all read at the
+// returned slot. Every account must exist.
+func GetMultipleAccountsData(publicKeys []solana.PublicKey) ([][]byte, uint64, error) {
+	params := map[string]interface{}{
+		"encoding":   "base64",
+		"commitment": "confirmed",
+	}
+
+	reqParams := []interface{}{
+		publicKeys,
+		params,
+	}
+
+	response, err := CallRPC("getMultipleAccounts", reqParams)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result multipleAccountsResult
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		return nil, 0, err
+	}
+
+	if len(result.Value) != len(publicKeys) {
+		return nil, 0, errors.New("unexpected number of accounts")
+	}
+
+	accounts := make([][]byte, len(publicKeys))
+	for i, value := range result.Value {
+		if value == nil || len(value.Data) == 0 {
+			return nil, 0, fmt.Errorf("account %s not found", publicKeys[i])
+		}
+
+		accounts[i], err = base64.StdEncoding.DecodeString(value.Data[0])
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return accounts, result.Context.Slot, nil
+}
+
+// GetAccountOwner returns the program that owns an account, which must exist.
+func GetAccountOwner(publicKey solana.PublicKey) (solana.PublicKey, error) {
+	var zero uint64
+	resp, err := GetAccountInfo(publicKey, &rpc.DataSlice{Offset: &zero, Length: &zero})
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+
+	if resp == nil || resp.Value == nil {
+		return solana.PublicKey{}, errors.New("account not found")
+	}
+
+	return solana.PublicKeyFromBase58(resp.Value.Owner)
+}
+
+// getAccountData returns the decoded data of an account, which must exist.
+func getAccountData(publicKey solana.PublicKey) ([]byte, error) {
+	resp, err := GetAccountInfo(publicKey, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp == nil || resp.Value == nil || len(resp.Value.Data) == 0 {
+		return nil, errors.New("account not found")
+	}
+
+	return base64.StdEncoding.DecodeString(resp.Value.Data[0])
+}
+
+func GetCpmmPoolState(poolId *solana.PublicKey) (*coder.CpmmPoolState, error) {
+	data, err := getAccountData(*poolId)
+	if err != nil {
+		return &coder.CpmmPoolState{}, err
+	}
+
+	state, err := coder.NewRaydiumCpmmCoder().DecodePoolState(data)
+	if err != nil {
+		return &coder.CpmmPoolState{}, err
+	}
+
+	return &state, nil
+}
+
+func GetClmmPoolState(poolId *solana.PublicKey) (*coder.ClmmPoolState, error) {
+	data, err := getAccountData(*poolId)
+	if err != nil {
+		return &coder.ClmmPoolState{}, err
+	}
+
+	state, err := coder.NewRaydiumClmmCoder().DecodePoolState(data)
+	if err != nil {
+		return &coder.ClmmPoolState{}, err
+	}
+
+	return &state, nil
+}
+
+func GetClmmPersonalPosition(positionId *solana.PublicKey) (*coder.ClmmPersonalPosition, error) {
+	data, err := getAccountData(*positionId)
+	if err != nil {
+		return &coder.ClmmPersonalPosition{}, err
+	}
+
+	position, err := coder.NewRaydiumClmmCoder().DecodePersonalPosition(data)
+	if err != nil {
+		return &coder.ClmmPersonalPosition{}, err
+	}
+
+	return &position, nil
+}
+
+func GetDlmmLbPair(pairId *solana.PublicKey) (*coder.DlmmLbPair, error) {
+	data, err := getAccountData(*pairId)
+	if err != nil {
+		return &coder.DlmmLbPair{}, err
+	}
+
+	state, err := coder.NewMeteoraDlmmCoder().DecodeLbPair(data)
+	if err != nil {
+		return &coder.DlmmLbPair{}, err
+	}
+
+	return &state, nil
+}
+
+func GetDammPool(poolId *solana.PublicKey) (*coder.DammPool, error) {
+	data, err := getAccountData(*poolId)
+	if err != nil {
+		return &coder.DammPool{}, err
+	}
+
+	state, err := coder.NewMeteoraDammCoder().DecodePool(data)
+	if err != nil {
+		return &coder.DammPool{}, err
+	}
+
+	return &state, nil
+}
+
+func GetDammVault(vaultId *solana.PublicKey) (*coder.DammVault, error) {
+	data, err := getAccountData(*vaultId)
+	if err != nil {
+		return &coder.DammVault{}, err
+	}
+
+	state, err := coder.NewMeteoraDammCoder().DecodeVault(data)
+	if err != nil {
+		return &coder.DammVault{}, err
+	}
+
+	return &state, nil
+}
+
+func GetWhirlpool(poolId *solana.PublicKey) (*coder.WhirlpoolState, error) {
+	data, err := getAccountData(*poolId)
+	if err != nil {
+		return &coder.WhirlpoolState{}, err
+	}
+
+	state, err := coder.NewOrcaWhirlpoolCoder().DecodeWhirlpool(data)
+	if err != nil {
+		return &coder.WhirlpoolState{}, err
+	}
+
+	return &state, nil
+}
+
+func GetWhirlpoolPosition(positionId *solana.PublicKey) (*coder.WhirlpoolPosition, error) {
+	data, err := getAccountData(*positionId)
+	if err != nil {
+		return &coder.WhirlpoolPosition{}, err
+	}
+
+	position, err := coder.NewOrcaWhirlpoolCoder().DecodePosition(data)
+	if err != nil {
+		return &coder.WhirlpoolPosition{}, err
+	}
+
+	return &position, nil
+}
+
+// GetTokenMint returns the state of a mint with the token program that owns it.
+func GetTokenMint(mint *solana.PublicKey) (*coder.TokenMint, solana.PublicKey, error) {
+	resp, err := GetAccountInfo(*mint, nil)
+	if err != nil {
+		return &coder.TokenMint{}, solana.PublicKey{}, err
+	}
+
+	if resp == nil || resp.Value == nil || len(resp.Value.Data) == 0 {
+		return &coder.TokenMint{}, solana.PublicKey{}, errors.New("account not found")
+	}
+
+	program, err := solana.PublicKeyFromBase58(resp.Value.Owner)
+	if err != nil {
+		return &coder.TokenMint{}, solana.PublicKey{}, err
+	}
+
+	var c *coder.SplTokenCoder
+	switch program {
+	case config.TOKEN_PROGRAM_ID:
+		c = coder.NewSplTokenCoder()
+	case config.TOKEN_2022_PROGRAM_ID:
+		c = coder.NewToken2022Coder()
+	default:
+		return &coder.TokenMint{}, program, errors.New("mint is not owned by a token program")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(resp.Value.Data[0])
+	if err != nil {
+		return &coder.TokenMint{}, program, err
+	}
+
+	state, err := c.DecodeMint(data)
+	if err != nil {
+		return &coder.TokenMint{}, program, err
+	}
+
+	return &state, program, nil
+}
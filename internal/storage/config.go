@@ -1,13 +1,22 @@
 package storage
 
 const (
-	KEY_POOLKEYS   = "storage::pool_keys"
-	KEY_LOOKUP     = "storage::lookup"
-	KEY_TRACKEDAMM = "storage::tracked_amm"
-	KEY_CHUNK      = "storage::chunk"
+	KEY_POOLKEYS      = "storage::pool_keys"
+	KEY_LOOKUP        = "storage::lookup"
+	KEY_TRACKEDAMM    = "storage::tracked_amm"
+	KEY_CHUNK         = "storage::chunk"
+	KEY_POSITION_POOL = "storage::position_pool"
+	KEY_LP_MINT_POOL  = "storage::lp_mint_pool"
+	KEY_LP_PROFILE    = "storage::lp_profile"
+	KEY_MINT_POOL     = "storage::mint_pool"
+	KEY_MINT_RISK     = "storage::mint_risk"
 )
 
 const (
-	TABLE_NAME_AMM   = "amms"
-	TABLE_NAME_TRADE = "trades"
+	TABLE_NAME_AMM           = "amms"
+	TABLE_NAME_TRADE         = "trades"
+	TABLE_NAME_LIQUIDITY     = "liquidity_events"
+	TABLE_NAME_PUMP          = "pump_tokens"
+	TABLE_NAME_TOKEN_RISK    = "token_risk_events"
+	TABLE_NAME_POOL_SNAPSHOT = "pool_snapshots"
 )
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/utils"
+)
+
+const (
+	LIQUIDITY_ADD    = "ADD"
+	LIQUIDITY_REMOVE = "REMOVE"
+)
+
+// Classifications of withdraws
+const (
+	WITHDRAW_FULL_RUG     = "FULL_RUG"
+	WITHDRAW_PARTIAL_PULL = "PARTIAL_PULL"
+	WITHDRAW_ROUTINE      = "ROUTINE"
+)
+
+type liquidityStorage struct {
+	client *sql.DB
+}
+
+func NewLiquidityStorage(client *sql.DB) *liquidityStorage {
+	return &liquidityStorage{client: client}
+}
+
+func (s *liquidityStorage) Set(event *types.LiquidityEvent) error {
+	columns := utils.BuildInsertQuery(event)
+
+	query := fmt.Sprintf(`INSERT INTO %s`, TABLE_NAME_LIQUIDITY) + columns
+	unpacked := utils.UnpackStruct(event)
+
+	_, err := s.client.Exec(query, unpacked...)
+	if err != nil {
+		log.Print(err)
+		return fmt.Errorf("failed to insert liquidity event: %w", err)
+	}
+	return nil
+}
+
+func (s *liquidityStorage) Search(filter types.MySQLFilter) ([]*types.LiquidityEvent, error) {
+	ctx := context.Background()
+
+	query, values := utils.BuildSearchQuery(TABLE_NAME_LIQUIDITY, &types.LiquidityEvent{}, filter)
+
+	rows, err := s.client.QueryContext(ctx, query, values...)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	defer rows.Close()
+
+	var events []*types.LiquidityEvent
+
+	var ammId string
+
+	for rows.Next() {
+		var e types.LiquidityEvent
+
+		err = rows.Scan(
+			&ammId,
+			&e.Action,
+			&e.BaseAmount,
+			&e.QuoteAmount,
+			&e.LpAmount,
+			&e.Signature,
+			&e.Signer,
+			&e.Slot,
+			&e.Timestamp,
+			&e.Classification,
+			&e.RemovedPct,
+			&e.LpBurnedPct,
+			&e.QuoteMint,
+			&e.QuoteReserve,
+			&e.UsdReserve,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		ammIdPk, err := solana.PublicKeyFromBase58(ammId)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		e.AmmId = &ammIdPk
+
+		events = append(events, &e)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+	"github.com/redis/go-redis/v9"
+)
+
+// Burns and transfers name the LP mint rather than the pool, so the pool of every
+// LP mint is kept under the mint's key.
+func SetLpMintPool(client *redis.Client, lpMint *solana.PublicKey, ammId *solana.PublicKey) error {
+	ctx := context.Background()
+	return client.HSet(ctx, lpMint.String(), KEY_LP_MINT_POOL, ammId.String()).Err()
+}
+
+func GetLpMintPool(client *redis.Client, lpMint *solana.PublicKey) (*solana.PublicKey, error) {
+	ctx := context.Background()
+	data, err := client.HGet(ctx, lpMint.String(), KEY_LP_MINT_POOL).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, errors.New("key not found")
+		}
+		return nil, err
+	}
+
+	ammId, err := solana.PublicKeyFromBase58(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ammId, nil
+}
+
+// The LP profile is kept next to the tracker status of the pool.
+func SetLpProfile(client *redis.Client, profile *types.LpProfile) error {
+	ctx := context.Background()
+
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+
+	return client.HSet(ctx, profile.AmmId.String(), KEY_LP_PROFILE, data).Err()
+}
+
+func GetLpProfile(client *redis.Client, ammId *solana.PublicKey) (*types.LpProfile, error) {
+	ctx := context.Background()
+	data, err := client.HGet(ctx, ammId.String(), KEY_LP_PROFILE).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, errors.New("key not found")
+		}
+		return nil, err
+	}
+
+	var profile types.LpProfile
+	if err := json.Unmarshal([]byte(data), &profile); err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+	"github.com/redis/go-redis/v9"
+)
+
+// Token instructions name the mint rather than the pool, so the token of every
+// tracked pool is kept under the mint's key.
+func SetMintPool(client *redis.Client, mint *solana.PublicKey, ammId *solana.PublicKey) error {
+	ctx := context.Background()
+	return client.HSet(ctx, mint.String(), KEY_MINT_POOL, ammId.String()).Err()
+}
+
+func GetMintPool(client *redis.Client, mint *solana.PublicKey) (*solana.PublicKey, error) {
+	ctx := context.Background()
+	data, err := client.HGet(ctx, mint.String(), KEY_MINT_POOL).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, errors.New("key not found")
+		}
+		return nil, err
+	}
+
+	ammId, err := solana.PublicKeyFromBase58(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ammId, nil
+}
+
+// The mint risk profile is kept next to the tracker status of the pool.
+func SetMintRisk(client *redis.Client, profile *types.MintRiskProfile) error {
+	ctx := context.Background()
+
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+
+	return client.HSet(ctx, profile.AmmId.String(), KEY_MINT_RISK, data).Err()
+}
+
+func GetMintRisk(client *redis.Client, ammId *solana.PublicKey) (*types.MintRiskProfile, error) {
+	ctx := context.Background()
+	data, err := client.HGet(ctx, ammId.String(), KEY_MINT_RISK).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, errors.New("key not found")
+		}
+		return nil, err
+	}
+
+	var profile types.MintRiskProfile
+	if err := json.Unmarshal([]byte(data), &profile); err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
@@ -27,6 +27,11 @@ func SetPoolKeys(client *redis.Client, pKey *types.RaydiumPoolKeys) error {
 		return err
 	}
 
+	// Concentrated liquidity pools have no LP mint
+	if !pKey.LpMint.IsZero() {
+		return SetLpMintPool(client, &pKey.LpMint, &pKey.ID)
+	}
+
 	return nil
 }
 
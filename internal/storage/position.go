@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/redis/go-redis/v9"
+)
+
+// Closing a concentrated liquidity position does not name the pool, so the pool of
+// every position seen in a liquidity instruction is kept under the position's key.
+func SetPositionPool(client *redis.Client, positionId *solana.PublicKey, poolId *solana.PublicKey) error {
+	ctx := context.Background()
+	return client.HSet(ctx, positionId.String(), KEY_POSITION_POOL, poolId.String()).Err()
+}
+
+func GetPositionPool(client *redis.Client, positionId *solana.PublicKey) (*solana.PublicKey, error) {
+	ctx := context.Background()
+	data, err := client.HGet(ctx, positionId.String(), KEY_POSITION_POOL).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, errors.New("key not found")
+		}
+		return nil, err
+	}
+
+	poolId, err := solana.PublicKeyFromBase58(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &poolId, nil
+}
@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/utils"
+)
+
+type pumpStorage struct {
+	client *sql.DB
+}
+
+func NewPumpStorage(client *sql.DB) *pumpStorage {
+	return &pumpStorage{client: client}
+}
+
+// Set stores a newly created token. A token that is already stored is left as is,
+// so replaying a create does not reset its migration.
+func (s *pumpStorage) Set(token *types.PumpToken) error {
+	columns := utils.BuildInsertQuery(token)
+
+	query := fmt.Sprintf(`INSERT IGNORE INTO %s`, TABLE_NAME_PUMP) + columns
+	unpacked := utils.UnpackStruct(token)
+
+	_, err := s.client.Exec(query, unpacked...)
+	if err != nil {
+		log.Print(err)
+		return fmt.Errorf("failed to insert pump token: %w", err)
+	}
+	return nil
+}
+
+func (s *pumpStorage) SetMigrated(mint *solana.PublicKey, signature string, slot uint64) error {
+	query := fmt.Sprintf(`UPDATE %s SET migrated_signature = ?, migrated_slot = ? WHERE mint = ?`, TABLE_NAME_PUMP)
+
+	_, err := s.client.Exec(query, signature, slot, mint.String())
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+	}
+	return nil
+}
+
+// SetAmm links the pool created for the token. It reports whether the mint is a
+// stored pump.fun token.
+func (s *pumpStorage) SetAmm(mint *solana.PublicKey, ammId *solana.PublicKey) (bool, error) {
+	query := fmt.Sprintf(`UPDATE %s SET amm_id = ? WHERE mint = ?`, TABLE_NAME_PUMP)
+
+	result, err := s.client.Exec(query, ammId.String(), mint.String())
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", ErrExecuteStatement, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", ErrRetrieveRows, err)
+	}
+
+	return affected > 0, nil
+}
+
+func (s *pumpStorage) Search(filter types.MySQLFilter) ([]*types.PumpToken, error) {
+	ctx := context.Background()
+
+	query, values := utils.BuildSearchQuery(TABLE_NAME_PUMP, &types.PumpToken{}, filter)
+
+	rows, err := s.client.QueryContext(ctx, query, values...)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	defer rows.Close()
+
+	var tokens []*types.PumpToken
+
+	var mint, bondingCurve, creator string
+
+	for rows.Next() {
+		var t types.PumpToken
+
+		err = rows.Scan(
+			&mint,
+			&bondingCurve,
+			&creator,
+			&t.Name,
+			&t.Symbol,
+			&t.Uri,
+			&t.CreatedSlot,
+			&t.CreatedSignature,
+			&t.MigratedSlot,
+			&t.MigratedSignature,
+			&t.AmmId,
+			&t.Timestamp,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		keys := make([]solana.PublicKey, 3)
+		for i, key := range []string{mint, bondingCurve, creator} {
+			keys[i], err = solana.PublicKeyFromBase58(key)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+			}
+		}
+
+		t.Mint = &keys[0]
+		t.BondingCurve = &keys[1]
+		t.Creator = &keys[2]
+
+		tokens = append(tokens, &t)
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/utils"
+)
+
+const (
+	RISK_MINT_AUTHORITY_CHANGED   = "MINT_AUTHORITY_CHANGED"
+	RISK_FREEZE_AUTHORITY_CHANGED = "FREEZE_AUTHORITY_CHANGED"
+	RISK_MINTED                   = "MINTED"
+	RISK_ACCOUNT_FROZEN           = "ACCOUNT_FROZEN"
+	RISK_TRANSFER_FEE_CHANGED     = "TRANSFER_FEE_CHANGED"
+	RISK_TRANSFER_HOOK_CHANGED    = "TRANSFER_HOOK_CHANGED"
+)
+
+type riskStorage struct {
+	client *sql.DB
+}
+
+func NewRiskStorage(client *sql.DB) *riskStorage {
+	return &riskStorage{client: client}
+}
+
+func (s *riskStorage) Set(event *types.TokenRiskEvent) error {
+	columns := utils.BuildInsertQuery(event)
+
+	query := fmt.Sprintf(`INSERT INTO %s`, TABLE_NAME_TOKEN_RISK) + columns
+	unpacked := utils.UnpackStruct(event)
+
+	_, err := s.client.Exec(query, unpacked...)
+	if err != nil {
+		log.Print(err)
+		return fmt.Errorf("failed to insert token risk event: %w", err)
+	}
+	return nil
+}
+
+func (s *riskStorage) Search(filter types.MySQLFilter) ([]*types.TokenRiskEvent, error) {
+	ctx := context.Background()
+
+	query, values := utils.BuildSearchQuery(TABLE_NAME_TOKEN_RISK, &types.TokenRiskEvent{}, filter)
+
+	rows, err := s.client.QueryContext(ctx, query, values...)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	defer rows.Close()
+
+	var events []*types.TokenRiskEvent
+
+	var ammId, mint string
+
+	for rows.Next() {
+		var e types.TokenRiskEvent
+
+		err = rows.Scan(
+			&ammId,
+			&mint,
+			&e.Event,
+			&e.Account,
+			&e.Amount,
+			&e.Signature,
+			&e.Slot,
+			&e.Timestamp,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		keys := make([]solana.PublicKey, 2)
+		for i, key := range []string{ammId, mint} {
+			keys[i], err = solana.PublicKeyFromBase58(key)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+			}
+		}
+
+		e.AmmId = &keys[0]
+		e.Mint = &keys[1]
+
+		events = append(events, &e)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/types"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/utils"
+)
+
+type snapshotStorage struct {
+	client *sql.DB
+}
+
+func NewSnapshotStorage(client *sql.DB) *snapshotStorage {
+	return &snapshotStorage{client: client}
+}
+
+func (s *snapshotStorage) Set(snapshot *types.PoolSnapshot) error {
+	columns := utils.BuildInsertQuery(snapshot)
+
+	query := fmt.Sprintf(`INSERT INTO %s`, TABLE_NAME_POOL_SNAPSHOT) + columns
+	unpacked := utils.UnpackStruct(snapshot)
+
+	_, err := s.client.Exec(query, unpacked...)
+	if err != nil {
+		log.Print(err)
+		return fmt.Errorf("failed to insert pool snapshot: %w", err)
+	}
+	return nil
+}
+
+func (s *snapshotStorage) Search(filter types.MySQLFilter) ([]*types.PoolSnapshot, error) {
+	ctx := context.Background()
+
+	query, values := utils.BuildSearchQuery(TABLE_NAME_POOL_SNAPSHOT, &types.PoolSnapshot{}, filter)
+
+	rows, err := s.client.QueryContext(ctx, query, values...)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrExecuteQuery, err)
+	}
+
+	defer rows.Close()
+
+	var snapshots []*types.PoolSnapshot
+
+	var ammId string
+
+	for rows.Next() {
+		var p types.PoolSnapshot
+
+		err = rows.Scan(
+			&ammId,
+			&p.Slot,
+			&p.Signature,
+			&p.Stage,
+			&p.BaseReserve,
+			&p.QuoteReserve,
+			&p.LpSupply,
+			&p.QuoteMint,
+			&p.Price,
+			&p.LpValue,
+			&p.Timestamp,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		ammIdPk, err := solana.PublicKeyFromBase58(ammId)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrScanData, err)
+		}
+
+		p.AmmId = &ammIdPk
+
+		snapshots = append(snapshots, &p)
+	}
+
+	return snapshots, nil
+}
@@ -3,9 +3,17 @@ package storage
 import "database/sql"
 
 var (
-	Trade *tradeStorage
+	Trade     *tradeStorage
+	Liquidity *liquidityStorage
+	Pump      *pumpStorage
+	TokenRisk *riskStorage
+	Snapshot  *snapshotStorage
 )
 
 func Init(client *sql.DB) {
 	Trade = NewTradeStorage(client)
+	Liquidity = NewLiquidityStorage(client)
+	Pump = NewPumpStorage(client)
+	TokenRisk = NewRiskStorage(client)
+	Snapshot = NewSnapshotStorage(client)
 }
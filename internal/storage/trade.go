@@ -36,7 +36,7 @@ func (s *tradeStorage) Set(trade *types.Trade) error {
 func (s *tradeStorage) Search(filter types.MySQLFilter) ([]*types.Trade, error) {
 	ctx := context.Background()
 
-	query, values := utils.BuildSearchQuery(TABLE_NAME_TRADE, filter)
+	query, values := utils.BuildSearchQuery(TABLE_NAME_TRADE, &types.Trade{}, filter)
 	stmt, err := s.client.PrepareContext(ctx, query)
 
 	if err != nil {
@@ -63,10 +63,14 @@ func (s *tradeStorage) Search(filter types.MySQLFilter) ([]*types.Trade, error)
 
 		err = rows.Scan(
 			&ammId,
+			&t.Dex,
 			&mint,
 			&t.Action,
 			&t.ComputeLimit,
 			&t.ComputePrice,
+			&t.PriorityFee,
+			&t.ComputeConsumed,
+			&t.ConsumedFee,
 			&t.Amount,
 			&t.Signature,
 			&t.Timestamp,
@@ -74,6 +78,16 @@ func (s *tradeStorage) Search(filter types.MySQLFilter) ([]*types.Trade, error)
 			&t.TipAmount,
 			&t.Status,
 			&t.Signer,
+			&t.Route,
+			&t.OuterProgram,
+			&t.SwapType,
+			&t.AmountIn,
+			&t.AmountOut,
+			&t.IntermediateMint,
+			&t.QuoteMint,
+			&t.QuoteAmount,
+			&t.QuoteValue,
+			&t.UsdValue,
 		)
 
 		if err != nil {
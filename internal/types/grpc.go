@@ -1,7 +0,0 @@
-package types
-
-type GrpcConfig struct {
-	Addr               string
-	Token              string
-	InsecureConnection bool
-}
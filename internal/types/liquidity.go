@@ -0,0 +1,26 @@
+package types
+
+import "github.com/gagliardetto/solana-go"
+
+// LiquidityEvent records liquidity entering or leaving a pool. Amounts are the
+// absolute change in the pool vaults and in the provider's LP balance. Withdraws are
+// classified against the rug thresholds, with the share of the reserves removed and
+// of the LP supply burned as percentages, and the quote left in the pool in quote
+// units and, when it can be normalised, in USD.
+type LiquidityEvent struct {
+	AmmId          *solana.PublicKey `json:"amm_id"`
+	Action         string            `json:"action"`
+	BaseAmount     string            `json:"base_amount"`
+	QuoteAmount    string            `json:"quote_amount"`
+	LpAmount       string            `json:"lp_amount"`
+	Signature      string            `json:"signature"`
+	Signer         string            `json:"signer"`
+	Slot           uint64            `json:"slot"`
+	Timestamp      int64             `json:"timestamp"`
+	Classification string            `json:"classification"`
+	RemovedPct     float64           `json:"removed_pct"`
+	LpBurnedPct    float64           `json:"lp_burned_pct"`
+	QuoteMint      string            `json:"quote_mint"`
+	QuoteReserve   float64           `json:"quote_reserve"`
+	UsdReserve     float64           `json:"usd_reserve"`
+}
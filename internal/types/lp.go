@@ -0,0 +1,27 @@
+package types
+
+import "github.com/gagliardetto/solana-go"
+
+// LpProfile is how a pool's LP tokens are held. Shares are fractions of the LP
+// supply the pool program reports, which burning LP tokens does not reduce.
+type LpProfile struct {
+	AmmId       *solana.PublicKey `json:"amm_id"`
+	LpMint      *solana.PublicKey `json:"lp_mint"`
+	Creator     string            `json:"creator"`
+	Supply      uint64            `json:"supply"`
+	Burned      uint64            `json:"burned"`
+	Locked      uint64            `json:"locked"`
+	CreatorHeld uint64            `json:"creator_held"`
+	BurnedPct   float64           `json:"burned_pct"`
+	LockedPct   float64           `json:"locked_pct"`
+	CreatorPct  float64           `json:"creator_pct"`
+	LastUpdated int64             `json:"last_updated"`
+}
+
+// TrackerStatus is the tracking status of a pool with its LP profile and the risk
+// profile of its token, when known.
+type TrackerStatus struct {
+	Tracker   *Tracker         `json:"tracker"`
+	LpProfile *LpProfile       `json:"lp_profile"`
+	MintRisk  *MintRiskProfile `json:"mint_risk"`
+}
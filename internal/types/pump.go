@@ -0,0 +1,20 @@
+package types
+
+import "github.com/gagliardetto/solana-go"
+
+// PumpToken follows a token from its pump.fun launch to the pool it migrates into.
+// The migration and AMM fields stay empty until those happen.
+type PumpToken struct {
+	Mint              *solana.PublicKey `json:"mint"`
+	BondingCurve      *solana.PublicKey `json:"bonding_curve"`
+	Creator           *solana.PublicKey `json:"creator"`
+	Name              string            `json:"name"`
+	Symbol            string            `json:"symbol"`
+	Uri               string            `json:"uri"`
+	CreatedSlot       uint64            `json:"created_slot"`
+	CreatedSignature  string            `json:"created_signature"`
+	MigratedSlot      uint64            `json:"migrated_slot"`
+	MigratedSignature string            `json:"migrated_signature"`
+	AmmId             string            `json:"amm_id"`
+	Timestamp         int64             `json:"timestamp"`
+}
@@ -0,0 +1,12 @@
+package types
+
+import "github.com/gagliardetto/solana-go"
+
+// QuoteMint is a mint pools are priced in. Usd marks stablecoins valued at one
+// dollar.
+type QuoteMint struct {
+	Mint     solana.PublicKey
+	Symbol   string
+	Decimals uint8
+	Usd      bool
+}
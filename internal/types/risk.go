@@ -0,0 +1,38 @@
+package types
+
+import "github.com/gagliardetto/solana-go"
+
+// TokenRiskEvent is a use of a mint's authorities on the token of a tracked pool.
+// Account is the new authority or hook program, the frozen account or the account
+// minted to, and stays empty when an authority is removed. Amount is the amount
+// minted, or the new fee in basis points when the transfer fee changes.
+type TokenRiskEvent struct {
+	AmmId     *solana.PublicKey `json:"amm_id"`
+	Mint      *solana.PublicKey `json:"mint"`
+	Event     string            `json:"event"`
+	Account   string            `json:"account"`
+	Amount    uint64            `json:"amount"`
+	Signature string            `json:"signature"`
+	Slot      uint64            `json:"slot"`
+	Timestamp int64             `json:"timestamp"`
+}
+
+// MintRiskProfile is what the mint of a pool's token allows its authorities to do,
+// read when the pool starts being tracked. Authorities, the delegate and the hook
+// program are null when unset.
+type MintRiskProfile struct {
+	AmmId                  *solana.PublicKey `json:"amm_id"`
+	Mint                   *solana.PublicKey `json:"mint"`
+	TokenProgram           string            `json:"token_program"`
+	Token2022              bool              `json:"token_2022"`
+	MintAuthority          *solana.PublicKey `json:"mint_authority"`
+	FreezeAuthority        *solana.PublicKey `json:"freeze_authority"`
+	Supply                 uint64            `json:"supply"`
+	Decimals               uint8             `json:"decimals"`
+	TransferHook           *solana.PublicKey `json:"transfer_hook"`
+	PermanentDelegate      *solana.PublicKey `json:"permanent_delegate"`
+	TransferFee            bool              `json:"transfer_fee"`
+	TransferFeeBasisPoints uint16            `json:"transfer_fee_basis_points"`
+	MaximumFee             uint64            `json:"maximum_fee"`
+	LastUpdated            int64             `json:"last_updated"`
+}
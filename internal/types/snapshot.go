@@ -0,0 +1,21 @@
+package types
+
+import "github.com/gagliardetto/solana-go"
+
+// PoolSnapshot is the state of a constant product pool before or after a transaction,
+// as told by Stage. Reserves are the raw vault balances less what the pool owes as
+// fees. Price is the pool's token in its quote, and LpValue is the quote one LP token
+// is worth, both in quote units.
+type PoolSnapshot struct {
+	AmmId        *solana.PublicKey `json:"amm_id"`
+	Slot         uint64            `json:"slot"`
+	Signature    string            `json:"signature"`
+	Stage        string            `json:"stage"`
+	BaseReserve  uint64            `json:"base_reserve"`
+	QuoteReserve uint64            `json:"quote_reserve"`
+	LpSupply     uint64            `json:"lp_supply"`
+	QuoteMint    string            `json:"quote_mint"`
+	Price        float64           `json:"price"`
+	LpValue      float64           `json:"lp_value"`
+	Timestamp    int64             `json:"timestamp"`
+}
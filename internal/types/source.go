@@ -0,0 +1,16 @@
+package types
+
+import "time"
+
+type SourceConfig struct {
+	Name               string
+	Kind               string
+	Addr               string
+	Token              string
+	InsecureConnection bool
+	Method             string
+	RpcUrl             string
+	Interval           time.Duration
+	Speed              float64
+	Programs           []string
+}
@@ -4,6 +4,7 @@ import "github.com/gagliardetto/solana-go"
 
 type Tracker struct {
 	AmmId       *solana.PublicKey
+	Dex         string
 	Status      string
 	LastUpdated int64
 }
@@ -2,17 +2,40 @@ package types
 
 import "github.com/gagliardetto/solana-go"
 
+// PriorityFee is the lamports paid for compute unit price on top of the base fee and
+// ComputeConsumed is the compute units the transaction actually used and ConsumedFee
+// the part of PriorityFee that paid for them.
+// AmountIn and AmountOut are the swap instruction arguments: the exact input and
+// minimum output for SwapBaseIn, the maximum input and exact output for SwapBaseOut.
+// Each pool of a two-hop swap instead has what it took in and paid out, and
+// IntermediateMint is the token passed from the first pool to the second.
+// QuoteAmount is the raw change in the pool's quote vault, QuoteValue the same in
+// quote units and UsdValue that normalised to USD, zero without a price.
 type Trade struct {
-	AmmId        *solana.PublicKey `json:"amm_id"`
-	Mint         *solana.PublicKey `json:"mint"`
-	Action       string            `json:"action"`
-	ComputeLimit uint64            `json:"compute_limit"`
-	ComputePrice uint64            `json:"compute_price"`
-	Amount       string            `json:"amount"`
-	Signature    string            `json:"signature"`
-	Timestamp    int64             `json:"timestamp"`
-	Tip          string            `json:"tip"`
-	TipAmount    int64             `json:"tip_amount"`
-	Status       string            `json:"status"`
-	Signer       string            `json:"signer"`
+	AmmId            *solana.PublicKey `json:"amm_id"`
+	Dex              string            `json:"dex"`
+	Mint             *solana.PublicKey `json:"mint"`
+	Action           string            `json:"action"`
+	ComputeLimit     uint64            `json:"compute_limit"`
+	ComputePrice     uint64            `json:"compute_price"`
+	PriorityFee      uint64            `json:"priority_fee"`
+	ComputeConsumed  uint64            `json:"compute_consumed"`
+	ConsumedFee      uint64            `json:"consumed_fee"`
+	Amount           string            `json:"amount"`
+	Signature        string            `json:"signature"`
+	Timestamp        int64             `json:"timestamp"`
+	Tip              string            `json:"tip"`
+	TipAmount        int64             `json:"tip_amount"`
+	Status           string            `json:"status"`
+	Signer           string            `json:"signer"`
+	Route            string            `json:"route"`
+	OuterProgram     string            `json:"outer_program"`
+	SwapType         string            `json:"swap_type"`
+	AmountIn         uint64            `json:"amount_in"`
+	AmountOut        uint64            `json:"amount_out"`
+	IntermediateMint string            `json:"intermediate_mint"`
+	QuoteMint        string            `json:"quote_mint"`
+	QuoteAmount      string            `json:"quote_amount"`
+	QuoteValue       float64           `json:"quote_value"`
+	UsdValue         float64           `json:"usd_value"`
 }
@@ -1,8 +1,9 @@
 package types
 
 type TxTokenBalance struct {
-	Mint    string `json:"mint"`
-	Owner   string `json:"owner"`
-	Amount  string `json:"amount"`
-	Decimal uint32 `json:"decimal"`
+	AccountIndex uint32 `json:"accountIndex"`
+	Mint         string `json:"mint"`
+	Owner        string `json:"owner"`
+	Amount       string `json:"amount"`
+	Decimal      uint32 `json:"decimal"`
 }
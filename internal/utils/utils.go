@@ -84,8 +84,17 @@ func BuildInsertQuery(i any) string {
 	return column + values
 }
 
-func BuildSearchQuery(tableName string, filter types.MySQLFilter) (string, []any) {
-	query := fmt.Sprintf(`SELECT * FROM %s`, tableName)
+// BuildSearchQuery selects the columns named by the json tags of model, in the order of
+// its fields, so rows scan the same way whatever order migrations added the columns in.
+func BuildSearchQuery(tableName string, model any, filter types.MySQLFilter) (string, []any) {
+	typ := reflect.TypeOf(model).Elem()
+
+	columns := make([]string, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		columns[i] = typ.Field(i).Tag.Get("json")
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s`, strings.Join(columns, ", "), tableName)
 	var values []any
 	for idx, q := range filter.Query {
 		if idx == 0 {
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
@@ -17,6 +19,7 @@ import (
 	"github.com/iqbalbaharum/lp-remove-tracker/internal/generators"
 	"github.com/iqbalbaharum/lp-remove-tracker/internal/handler"
 	bot "github.com/iqbalbaharum/lp-remove-tracker/internal/library"
+	"github.com/iqbalbaharum/lp-remove-tracker/internal/price"
 	"github.com/iqbalbaharum/lp-remove-tracker/internal/storage"
 )
 
@@ -32,16 +35,12 @@ func CreateServer() *Server {
 	return server
 }
 
-const (
-	PORT = 5000
-)
-
 func loadAdapter() {
 	adapter.GetRedisClient(0)
 }
 
 var (
-	grpcs            []*generators.GrpcClient
+	sources          []generators.Source
 	latestBlockhash  string
 	wsolTokenAccount solana.PublicKey
 	wg               sync.WaitGroup
@@ -49,6 +48,16 @@ var (
 )
 
 func main() {
+	recordPath := flag.String("record", "", "write every received transaction to this file")
+	replayPath := flag.String("replay", "", "process a recorded file instead of the configured sources, then exit")
+	replaySpeed := flag.Float64("speed", 0, "replay speed multiplier, 1 keeps the original timing and 0 replays as fast as possible")
+	replayMySql := flag.String("replay-mysql", "", "MySQL DSN a replay writes to instead of the configured one, required with -replay")
+	replayRedis := flag.String("replay-redis", "", "Redis address a replay writes to instead of the configured one, required with -replay")
+	verifyPath := flag.String("verify-swaps", "", "compare simulated AMM v4 swaps with the balance deltas in a recorded file, then exit")
+	verifyPools := flag.String("verify-pools", "", "pool states captured with the recording, defaults to the recording path with .pools.json appended")
+	verifyTolerance := flag.Float64("verify-tolerance", 0.01, "largest deviation of a simulated swap, in percent, before verification fails")
+	flag.Parse()
+
 	numCPU := runtime.NumCPU() * 2
 	maxProcs := runtime.GOMAXPROCS(0)
 	log.Printf("Number of logical CPUs available: %d", numCPU)
@@ -64,6 +73,20 @@ func main() {
 		return
 	}
 
+	// A replay processes its transactions like a live source, so it would write its
+	// trades, trackers and pool keys over the production ones
+	if *replayPath != "" {
+		if *replayMySql == "" || *replayRedis == "" {
+			log.Fatal("-replay needs -replay-mysql and -replay-redis so it doesn't write to the configured databases")
+		}
+
+		if *replayMySql == config.MySqlDsn || *replayRedis == config.RedisAddr {
+			log.Fatal("-replay-mysql and -replay-redis must differ from the configured databases")
+		}
+
+		config.MySqlDsn, config.RedisAddr = *replayMySql, *replayRedis
+	}
+
 	err = adapter.InitRedisClients(config.RedisAddr, config.RedisPassword)
 	if err != nil {
 		log.Fatalf(fmt.Sprintf("Failed to initialize Redis clients: %v", err))
@@ -76,24 +99,75 @@ func main() {
 		return
 	}
 
+	if err := price.Init(config.Price); err != nil {
+		log.Fatalf("Failed to initialize price source: %v", err)
+		return
+	}
+
+	if err := bot.RegisterIdls(config.Idls); err != nil {
+		log.Fatalf("Failed to load IDLs: %v", err)
+		return
+	}
+
 	log.Print("Initialized ENVIRONMENT successfully")
 
-	client, err := generators.GrpcConnect(config.GRPC1.Addr, config.GRPC1.InsecureConnection)
+	mySqlClient, err := adapter.GetMySQLClient()
 
 	if err != nil {
-		log.Fatalf("Error in GRPC connection: %s ", err)
-		return
+		panic(err)
 	}
 
-	client2, err := generators.GrpcConnect(config.GRPC2.Addr, config.GRPC2.InsecureConnection)
+	storage.Init(mySqlClient)
 
-	grpcs = append(grpcs, client, client2)
+	if *verifyPath != "" {
+		if *verifyPools == "" {
+			*verifyPools = bot.PoolStatesPath(*verifyPath)
+		}
 
-	if err != nil {
-		log.Fatalf("Error in GRPC connection: %s ", err)
+		if err := bot.VerifyRecordedSwaps(*verifyPath, *verifyPools, *verifyTolerance); err != nil {
+			log.Fatalf("Failed to verify swaps in %s: %v", *verifyPath, err)
+		}
 		return
 	}
 
+	var replay *generators.ReplaySource
+
+	if *replayPath != "" {
+		// A single worker keeps the replay in recorded order so runs are repeatable
+		replay = generators.NewReplaySource("replay", *replayPath, *replaySpeed)
+		sources = append(sources, replay)
+		numCPU = 1
+	} else {
+		if err := bot.WatchTrackedMints(); err != nil {
+			log.Printf("Failed to watch the mints of tracked pools: %v", err)
+		}
+
+		for _, cfg := range config.Sources {
+			source, err := generators.NewSource(cfg)
+			if err != nil {
+				log.Fatalf("Error in %s source: %s ", cfg.Name, err)
+				return
+			}
+
+			sources = append(sources, source)
+		}
+	}
+
+	var recorder *generators.Recorder
+	var poolCapture *bot.PoolCapture
+
+	if *recordPath != "" {
+		recorder, err = generators.NewRecorder(*recordPath)
+		if err != nil {
+			log.Fatalf("Failed to create recording %s: %v", *recordPath, err)
+			return
+		}
+		defer recorder.Close()
+
+		// Swaps are verified later with the pool states of when they were recorded
+		poolCapture = bot.NewPoolCapture(bot.PoolStatesPath(*recordPath))
+	}
+
 	txChannel = make(chan generators.GeyserResponse)
 
 	var processed sync.Map
@@ -104,8 +178,15 @@ func main() {
 		go func() {
 			defer wg.Done()
 			for response := range txChannel {
-				if _, exists := processed.Load(response.MempoolTxns.Signature); !exists {
-					processed.Store(response.MempoolTxns.Signature, true)
+				if recorder != nil {
+					if err := recorder.Record(response); err != nil {
+						log.Printf("Failed to record %s: %v", response.MempoolTxns.Signature, err)
+					}
+					poolCapture.Observe(response)
+				}
+
+				// A transaction matching several subscriptions can reach two workers at once
+				if _, exists := processed.LoadOrStore(response.MempoolTxns.Signature, true); !exists {
 					bot.ProcessResponse(response)
 
 					time.AfterFunc(1*time.Minute, func() {
@@ -116,60 +197,34 @@ func main() {
 		}()
 	}
 
-	listenFor(
-		grpcs[0],
-		"triton",
-		[]string{
-			config.RAYDIUM_AMM_V4.String(),
-		}, txChannel, &wg)
-
-	listenFor(
-		grpcs[1],
-		"solana-tracker",
-		[]string{
-			config.RAYDIUM_AMM_V4.String(),
-		}, txChannel, &wg)
-
-	mySqlClient, err := adapter.GetMySQLClient()
-
-	if err != nil {
-		panic(err)
+	ctx := context.Background()
+	for _, source := range sources {
+		if err := source.Start(ctx, txChannel); err != nil {
+			if replay != nil {
+				log.Fatalf("Error starting replay: %v", err)
+			}
+			log.Printf("Error starting %s source: %v", source.Name(), err)
+		}
 	}
 
-	storage.Init(mySqlClient)
+	if replay != nil {
+		<-replay.Done()
+		close(txChannel)
+		wg.Wait()
+		return
+	}
 
 	server := CreateServer()
-	port := fmt.Sprintf(":%d", PORT)
+	port := fmt.Sprintf(":%d", config.HttpPort)
 	fmt.Printf("server running on port%s \n", port)
 
 	http.ListenAndServe(port, server.Router)
 
 	wg.Wait()
 
-	for i := 0; i < len(grpcs); i++ {
-		grpc := grpcs[i]
-		defer func() {
-
-			if err := grpc.CloseConnection(); err != nil {
-
-				log.Printf("Error closing gRPC connection: %v", err)
-			}
-		}()
-	}
-}
-
-// Listening geyser for new addresses
-func listenFor(client *generators.GrpcClient, name string, addresses []string, txChannel chan generators.GeyserResponse, wg *sync.WaitGroup) {
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		err := client.GrpcSubscribeByAddresses(
-			name,
-			config.GrpcToken,
-			addresses,
-			[]string{}, txChannel)
-		if err != nil {
-			log.Printf("Error in first gRPC subscription: %v", err)
+	for _, source := range sources {
+		if err := source.Stop(); err != nil {
+			log.Printf("Error stopping %s source: %v", source.Name(), err)
 		}
-	}()
+	}
 }
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS liquidity_events (
+    amm_id VARCHAR(255),
+    action VARCHAR(255),
+    base_amount BIGINT UNSIGNED,
+    quote_amount BIGINT UNSIGNED,
+    lp_amount BIGINT UNSIGNED,
+    signature VARCHAR(255),
+    signer VARCHAR(255),
+    slot BIGINT UNSIGNED,
+    timestamp INT,
+    classification VARCHAR(255) NOT NULL DEFAULT '',
+    removed_pct DOUBLE NOT NULL DEFAULT 0,
+    lp_burned_pct DOUBLE NOT NULL DEFAULT 0,
+    quote_mint VARCHAR(255) NOT NULL DEFAULT '',
+    quote_reserve DOUBLE NOT NULL DEFAULT 0,
+    usd_reserve DOUBLE NOT NULL DEFAULT 0
+);
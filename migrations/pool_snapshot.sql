@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS pool_snapshots (
+    amm_id VARCHAR(255),
+    slot BIGINT UNSIGNED,
+    signature VARCHAR(255) NOT NULL DEFAULT '',
+    stage VARCHAR(255) NOT NULL DEFAULT '',
+    base_reserve BIGINT UNSIGNED,
+    quote_reserve BIGINT UNSIGNED,
+    lp_supply BIGINT UNSIGNED,
+    quote_mint VARCHAR(255),
+    price DOUBLE,
+    lp_value DOUBLE,
+    timestamp INT,
+    INDEX (amm_id, slot)
+);
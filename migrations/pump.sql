@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS pump_tokens (
+    mint VARCHAR(255) PRIMARY KEY,
+    bonding_curve VARCHAR(255),
+    creator VARCHAR(255),
+    name VARCHAR(255),
+    symbol VARCHAR(255),
+    uri VARCHAR(1024),
+    created_slot BIGINT UNSIGNED,
+    created_signature VARCHAR(255),
+    migrated_slot BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    migrated_signature VARCHAR(255) NOT NULL DEFAULT '',
+    amm_id VARCHAR(255) NOT NULL DEFAULT '',
+    timestamp INT
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS token_risk_events (
+    amm_id VARCHAR(255),
+    mint VARCHAR(255),
+    event VARCHAR(255),
+    account VARCHAR(255),
+    amount BIGINT UNSIGNED,
+    signature VARCHAR(255),
+    slot BIGINT UNSIGNED,
+    timestamp INT
+);
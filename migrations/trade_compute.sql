@@ -0,0 +1,6 @@
+ALTER TABLE trades
+    MODIFY compute_limit INT UNSIGNED,
+    MODIFY compute_price BIGINT UNSIGNED,
+    ADD COLUMN priority_fee BIGINT UNSIGNED NOT NULL DEFAULT 0 AFTER compute_price,
+    ADD COLUMN compute_consumed BIGINT UNSIGNED NOT NULL DEFAULT 0 AFTER priority_fee,
+    ADD COLUMN consumed_fee BIGINT UNSIGNED NOT NULL DEFAULT 0 AFTER compute_consumed;
@@ -0,0 +1,2 @@
+ALTER TABLE trades
+    ADD COLUMN dex VARCHAR(255) NOT NULL DEFAULT '' AFTER amm_id;
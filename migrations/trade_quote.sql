@@ -0,0 +1,5 @@
+ALTER TABLE trades
+    ADD COLUMN quote_mint VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN quote_amount BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    ADD COLUMN quote_value DOUBLE NOT NULL DEFAULT 0,
+    ADD COLUMN usd_value DOUBLE NOT NULL DEFAULT 0;
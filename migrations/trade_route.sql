@@ -0,0 +1,3 @@
+ALTER TABLE trades
+    ADD COLUMN route VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN outer_program VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE trades
+    ADD COLUMN swap_type VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN amount_in BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    ADD COLUMN amount_out BIGINT UNSIGNED NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE trades
+    ADD COLUMN intermediate_mint VARCHAR(255) NOT NULL DEFAULT '' AFTER amount_out;